@@ -0,0 +1,40 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+
+	AuditFilm  = "film"
+	AuditActor = "actor"
+	AuditUser  = "user"
+)
+
+// AuditEntry records a change of a film, an actor or a user. Before and After are the JSON
+// snapshots of the entity around the change, Before is null for a create and After for a delete.
+type AuditEntry struct {
+	Id        int             `json:"id" db:"id"`
+	UserId    int             `json:"user_id" db:"user_id"`
+	Entity    string          `json:"entity" db:"entity"`
+	EntityId  int             `json:"entity_id" db:"entity_id"`
+	Action    string          `json:"action" db:"action"`
+	Before    json.RawMessage `json:"before" db:"before"`
+	After     json.RawMessage `json:"after" db:"after"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter selects the entries of the audit log, nil fields select everything. The
+// time range includes From and excludes To.
+type AuditFilter struct {
+	Entity   *string
+	EntityId *int
+	UserId   *int
+	From     *time.Time
+	To       *time.Time
+}
@@ -10,9 +10,50 @@ import (
 	"github.com/sirupsen/logrus"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 )
 
+const (
+	defaultPurgeRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval  = time.Hour
+)
+
+// getDuration parses the duration from the config value, falling back to def when it is not set.
+func getDuration(name, value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		logrus.Fatalf("invalid %s %q: expected positive duration", name, value)
+	}
+	return duration
+}
+
+// runPurge removes the entities deleted longer than retention ago right away and then every
+// interval, until ctx is done.
+func runPurge(ctx context.Context, trash service.Trash, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if purged, err := trash.Purge(retention); err != nil {
+			logrus.Errorf("purge of deleted entities failed with: %s", err)
+		} else if purged > 0 {
+			logrus.Infof("%d deleted entities were purged", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func main() {
 	logrus.SetFormatter(new(logrus.JSONFormatter))
 
@@ -20,9 +61,25 @@ func main() {
 
 	configs.ConnectToDb()
 
+	keys, err := service.LoadKeySet()
+	if err != nil {
+		logrus.Fatalf("loading signing keys failed with: %s", err)
+	}
+
 	repo := models_dao.NewRepository(configs.PsClient.DB)
-	serv := service.NewService(repo)
-	router := handlers.NewRouter(serv)
+	serv := service.NewService(repo, keys)
+	maxPageSize := 0
+	if value := configs.EnvMaxPageSize(); value != "" {
+		if maxPageSize, err = strconv.Atoi(value); err != nil {
+			logrus.Fatalf("invalid max page size %q: %s", value, err)
+		}
+	}
+	router := handlers.NewRouter(serv, maxPageSize)
+
+	retention := getDuration("purge retention", configs.EnvPurgeRetention(), defaultPurgeRetention)
+	interval := getDuration("purge interval", configs.EnvPurgeInterval(), defaultPurgeInterval)
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	go runPurge(purgeCtx, serv.Trash, retention, interval)
 
 	go func() {
 		if err := server.Run(router); err != nil {
@@ -38,6 +95,7 @@ func main() {
 	<-quit
 
 	logrus.Info("Filmoteka-app shut down")
+	stopPurge()
 
 	if err := server.ShutDown(context.Background()); err != nil {
 		logrus.Errorf("error occurred on server shut down: %s", err.Error())
@@ -0,0 +1,93 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type Collection struct {
+	Id          int       `json:"id" db:"id"`
+	OwnerId     int       `json:"owner_id" db:"owner_id"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	IsPublic    bool      `json:"public" db:"is_public"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type InputCollection struct {
+	Collection
+	Films []int `json:"films"`
+}
+
+func (i *InputCollection) UnmarshalJSON(data []byte) error {
+	result := struct {
+		Title       *string `json:"title"`
+		Description *string `json:"description"`
+		IsPublic    *bool   `json:"public"`
+		Films       []int   `json:"films"`
+	}{}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	if result.Title == nil || len(*result.Title) == 0 {
+		return errors.New("invalid state for required field(s)")
+	} else {
+		i.Title = *result.Title
+		if result.Description != nil {
+			i.Description = *result.Description
+		}
+		if result.IsPublic != nil {
+			i.IsPublic = *result.IsPublic
+		}
+		i.Films = result.Films
+	}
+	return nil
+}
+
+type CollectionListItem struct {
+	Collection
+	Films []Film `json:"films"`
+}
+
+type UpdateCollectionInput struct {
+	Id          *int    `json:"id"`
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	IsPublic    *bool   `json:"public"`
+	Films       *[]int  `json:"films"`
+}
+
+func (u *UpdateCollectionInput) UnmarshalJSON(data []byte) error {
+	type Result UpdateCollectionInput
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	if result.Title == nil && result.Description == nil && result.IsPublic == nil && result.Films == nil {
+		return errors.New("invalid state for required filed to update collection")
+	} else {
+		u.Title = result.Title
+		u.Description = result.Description
+		u.IsPublic = result.IsPublic
+		u.Films = result.Films
+	}
+	return nil
+}
+
+// UpdateValues returns the new values of the changed columns of the collection.
+func (u *UpdateCollectionInput) UpdateValues() map[string]interface{} {
+	values := make(map[string]interface{}, 3)
+	if u.Title != nil {
+		values["title"] = *u.Title
+	}
+	if u.Description != nil {
+		values["description"] = *u.Description
+	}
+	if u.IsPublic != nil {
+		values["is_public"] = *u.IsPublic
+	}
+	return values
+}
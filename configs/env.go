@@ -95,3 +95,164 @@ func EnvStarredTable() string {
 
 	return os.Getenv("STARREDTABLE")
 }
+
+func EnvGenreTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("GENRETABLE")
+}
+
+func EnvFilmGenreTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("FILMGENRETABLE")
+}
+
+func EnvCreditTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("CREDITTABLE")
+}
+
+func EnvReviewTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("REVIEWTABLE")
+}
+
+func EnvWatchlistTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("WATCHLISTTABLE")
+}
+
+func EnvCollectionTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("COLLECTIONTABLE")
+}
+
+func EnvCollectionFilmTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("COLLECTIONFILMTABLE")
+}
+
+func EnvRefreshTokenTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("REFRESHTOKENTABLE")
+}
+
+func EnvRevokedTokenTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("REVOKEDTOKENTABLE")
+}
+
+func EnvAuditTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("AUDITTABLE")
+}
+
+func EnvRevisionTable() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("REVISIONTABLE")
+}
+
+// EnvSigningKeys returns the JWT keys as a ';' separated list of "kid:alg:source" entries,
+// where source is the secret itself for HMAC and a path to a PEM private key otherwise.
+func EnvSigningKeys() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("JWTKEYS")
+}
+
+func EnvActiveSigningKey() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("JWTACTIVEKEY")
+}
+
+// EnvMaxPageSize returns the largest page size a client may request for paginated lists.
+func EnvMaxPageSize() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("MAXPAGESIZE")
+}
+
+// EnvSimilarityThreshold returns the least trigram similarity of a name to the searched
+// one for fuzzy search, between 0 and 1.
+func EnvSimilarityThreshold() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("SIMILARITYTHRESHOLD")
+}
+
+// EnvPurgeRetention returns how long the deleted films, actors and users are kept for
+// restore before the purge removes them, as a duration like 720h.
+func EnvPurgeRetention() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("PURGERETENTION")
+}
+
+// EnvPurgeInterval returns how often the purge of the deleted entities runs, as a duration.
+func EnvPurgeInterval() string {
+	err := godotenv.Load()
+	if err != nil {
+		logrus.Fatal("Error loading .env file")
+	}
+
+	return os.Getenv("PURGEINTERVAL")
+}
@@ -0,0 +1,29 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var CreditRoles = map[string]struct{}{
+	"director": {}, "writer": {}, "composer": {}, "producer": {}, "cinematographer": {},
+}
+
+type Credits map[string]Cast
+
+func (c *Credits) UnmarshalJSON(data []byte) error {
+	result := map[string]Cast{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	for role := range result {
+		if _, ok := CreditRoles[role]; !ok {
+			return errors.New("invalid role for credit: " + role)
+		}
+	}
+	*c = result
+	return nil
+}
+
+type FilmCredits map[string][]Film
@@ -0,0 +1,3 @@
+DROP TABLE film_genres;
+
+DROP TABLE genres;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE genres
+(
+    id   serial PRIMARY KEY,
+    name varchar(255) not null unique,
+    CHECK (LENGTH(name) > 0)
+);
+
+CREATE TABLE film_genres
+(
+    film_id  integer not null,
+    genre_id integer not null,
+    FOREIGN KEY (film_id) REFERENCES films (id) ON DELETE CASCADE,
+    FOREIGN KEY (genre_id) REFERENCES genres (id) ON DELETE CASCADE,
+    PRIMARY KEY (film_id, genre_id)
+);
@@ -0,0 +1 @@
+DROP TABLE credits;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE credits
+(
+    actor_id integer      not null,
+    film_id  integer      not null,
+    role     varchar(255) not null,
+    CHECK (role in ('director', 'writer', 'composer', 'producer', 'cinematographer')),
+    FOREIGN KEY (actor_id) REFERENCES actors (id) ON DELETE CASCADE,
+    FOREIGN KEY (film_id) REFERENCES films (id) ON DELETE CASCADE,
+    PRIMARY KEY (actor_id, film_id, role)
+);
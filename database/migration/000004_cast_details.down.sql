@@ -0,0 +1,3 @@
+ALTER TABLE starred_in
+    DROP COLUMN character_name,
+    DROP COLUMN billing_order;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE starred_in
+    ADD COLUMN character_name varchar(255) not null default '',
+    ADD COLUMN billing_order  integer      not null default 0,
+    ADD CHECK (billing_order >= 0);
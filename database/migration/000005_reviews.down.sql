@@ -0,0 +1,5 @@
+ALTER TABLE films
+    DROP COLUMN avg_rating,
+    DROP COLUMN votes;
+
+DROP TABLE reviews;
\ No newline at end of file
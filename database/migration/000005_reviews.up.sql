@@ -0,0 +1,19 @@
+CREATE TABLE reviews
+(
+    id         serial PRIMARY KEY,
+    user_id    integer   not null,
+    film_id    integer   not null,
+    rating     integer   not null,
+    CHECK (rating >= 0 and rating <= 10),
+    text       text      not null default '',
+    CHECK (LENGTH(text) <= 5000),
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now(),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (film_id) REFERENCES films (id) ON DELETE CASCADE,
+    UNIQUE (user_id, film_id)
+);
+
+ALTER TABLE films
+    ADD COLUMN avg_rating numeric(4, 2) not null default 0,
+    ADD COLUMN votes      integer       not null default 0;
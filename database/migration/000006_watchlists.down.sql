@@ -0,0 +1 @@
+DROP TABLE watchlist;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE watchlist
+(
+    user_id    integer      not null,
+    film_id    integer      not null,
+    status     varchar(255) not null,
+    CHECK (status = 'want' or status = 'watched'),
+    watched_on date,
+    CHECK (status = 'watched' or watched_on is null),
+    added_at   timestamp    not null default now(),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (film_id) REFERENCES films (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, film_id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE collection_films;
+
+DROP TABLE collections;
\ No newline at end of file
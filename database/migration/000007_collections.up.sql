@@ -0,0 +1,24 @@
+CREATE TABLE collections
+(
+    id          serial PRIMARY KEY,
+    owner_id    integer      not null,
+    title       varchar(255) not null,
+    CHECK (LENGTH(title) > 0 and LENGTH(title) <= 150),
+    description text         not null default '',
+    CHECK (LENGTH(description) <= 1000),
+    is_public   boolean      not null default false,
+    created_at  timestamp    not null default now(),
+    FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_collections_public ON collections (created_at DESC) WHERE is_public;
+
+CREATE TABLE collection_films
+(
+    collection_id integer not null,
+    film_id       integer not null,
+    position      integer not null,
+    FOREIGN KEY (collection_id) REFERENCES collections (id) ON DELETE CASCADE,
+    FOREIGN KEY (film_id) REFERENCES films (id) ON DELETE CASCADE,
+    PRIMARY KEY (collection_id, film_id)
+);
@@ -0,0 +1,6 @@
+DROP TABLE revoked_tokens;
+
+DROP TABLE refresh_tokens;
+
+ALTER TABLE users
+    DROP COLUMN token_version;
\ No newline at end of file
@@ -0,0 +1,21 @@
+ALTER TABLE users
+    ADD COLUMN token_version integer not null default 0;
+
+CREATE TABLE refresh_tokens
+(
+    id         serial PRIMARY KEY,
+    user_id    integer      not null,
+    token_hash varchar(64)  not null unique,
+    expires_at timestamp    not null,
+    revoked    boolean      not null default false,
+    created_at timestamp    not null default now(),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens (user_id);
+
+CREATE TABLE revoked_tokens
+(
+    jti        varchar(64) PRIMARY KEY,
+    expires_at timestamp   not null
+);
@@ -0,0 +1,10 @@
+UPDATE users
+SET user_role = 'regular'
+WHERE user_role IN ('editor', 'moderator');
+
+ALTER TABLE users
+    DROP CONSTRAINT users_user_role_check;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_user_role_check
+        CHECK (user_role = 'regular' or user_role = 'admin');
@@ -0,0 +1,7 @@
+DROP INDEX idx_films_title_id;
+
+DROP INDEX idx_films_issue_date_id;
+
+DROP INDEX idx_films_rating_id;
+
+DROP INDEX idx_films_avg_rating_id;
@@ -0,0 +1,7 @@
+CREATE INDEX idx_films_avg_rating_id ON films (avg_rating DESC, id DESC);
+
+CREATE INDEX idx_films_rating_id ON films (rating DESC, id DESC);
+
+CREATE INDEX idx_films_issue_date_id ON films (issue_date DESC, id DESC);
+
+CREATE INDEX idx_films_title_id ON films (title DESC, id DESC);
@@ -0,0 +1,16 @@
+DROP INDEX idx_films_search;
+
+DROP TRIGGER films_search_vector ON films;
+
+DROP FUNCTION films_search_vector_update();
+
+ALTER TABLE films
+    DROP COLUMN search_vector;
+
+DROP FUNCTION film_search_headline(text, text);
+
+DROP FUNCTION film_search_query(text);
+
+DROP FUNCTION film_search_vector(text, text);
+
+DROP TABLE search_dictionaries;
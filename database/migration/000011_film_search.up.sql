@@ -0,0 +1,88 @@
+-- Text search configurations used to index and to query films. A film is indexed with
+-- every dictionary, so after changing this table run
+-- UPDATE films SET search_vector = film_search_vector(title, description);
+CREATE TABLE search_dictionaries
+(
+    config regconfig PRIMARY KEY
+);
+
+INSERT INTO search_dictionaries (config)
+VALUES ('russian'),
+       ('english');
+
+CREATE FUNCTION film_search_vector(title text, description text) RETURNS tsvector AS
+$$
+DECLARE
+    cfg    regconfig;
+    result tsvector := ''::tsvector;
+BEGIN
+    FOR cfg IN SELECT config FROM search_dictionaries
+        LOOP
+            result := result || setweight(to_tsvector(cfg, coalesce(title, '')), 'A')
+                          || setweight(to_tsvector(cfg, coalesce(description, '')), 'B');
+        END LOOP;
+    RETURN result;
+END
+$$ LANGUAGE plpgsql STABLE;
+
+-- film_search_query matches a word in any of the dictionaries, the search text is read
+-- like in web search engines: quoted phrases, "or" and "-" to exclude a word.
+CREATE FUNCTION film_search_query(search text) RETURNS tsquery AS
+$$
+DECLARE
+    cfg    regconfig;
+    result tsquery;
+BEGIN
+    FOR cfg IN SELECT config FROM search_dictionaries
+        LOOP
+            IF result IS NULL THEN
+                result := websearch_to_tsquery(cfg, search);
+            ELSE
+                result := result || websearch_to_tsquery(cfg, search);
+            END IF;
+        END LOOP;
+    RETURN coalesce(result, websearch_to_tsquery('simple', search));
+END
+$$ LANGUAGE plpgsql STABLE;
+
+-- film_search_headline highlights the search words in the document with the first
+-- dictionary that finds them.
+CREATE FUNCTION film_search_headline(document text, search text) RETURNS text AS
+$$
+DECLARE
+    cfg      regconfig;
+    headline text;
+BEGIN
+    FOR cfg IN SELECT config FROM search_dictionaries
+        LOOP
+            headline := ts_headline(cfg, coalesce(document, ''), websearch_to_tsquery(cfg, search),
+                                    'StartSel=<b>, StopSel=</b>, MaxFragments=2, MaxWords=20, MinWords=5');
+            IF position('<b>' in headline) > 0 THEN
+                RETURN headline;
+            END IF;
+        END LOOP;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql STABLE;
+
+ALTER TABLE films
+    ADD COLUMN search_vector tsvector not null default ''::tsvector;
+
+CREATE FUNCTION films_search_vector_update() RETURNS trigger AS
+$$
+BEGIN
+    NEW.search_vector := film_search_vector(NEW.title, NEW.description);
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER films_search_vector
+    BEFORE INSERT OR UPDATE OF title, description
+    ON films
+    FOR EACH ROW
+EXECUTE FUNCTION films_search_vector_update();
+
+UPDATE films
+SET search_vector = film_search_vector(title, description);
+
+CREATE INDEX idx_films_search ON films USING GIN (search_vector);
@@ -0,0 +1,5 @@
+DROP INDEX idx_actors_full_name_trgm;
+
+DROP INDEX idx_actors_surname_trgm;
+
+DROP INDEX idx_actors_name_trgm;
@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_actors_name_trgm ON actors USING GIN (name gin_trgm_ops);
+
+CREATE INDEX idx_actors_surname_trgm ON actors USING GIN (surname gin_trgm_ops);
+
+CREATE INDEX idx_actors_full_name_trgm ON actors USING GIN ((name || ' ' || surname) gin_trgm_ops);
@@ -0,0 +1,5 @@
+DROP INDEX idx_actors_birthday_id;
+
+DROP INDEX idx_actors_surname_id;
+
+DROP INDEX idx_actors_name_id;
@@ -0,0 +1,5 @@
+CREATE INDEX idx_actors_name_id ON actors (name, id);
+
+CREATE INDEX idx_actors_surname_id ON actors (surname, id);
+
+CREATE INDEX idx_actors_birthday_id ON actors (birthday, id);
@@ -0,0 +1,5 @@
+DROP INDEX idx_actors_surname_prefix;
+
+DROP INDEX idx_actors_full_name_prefix;
+
+DROP INDEX idx_films_title_prefix;
@@ -0,0 +1,5 @@
+CREATE INDEX idx_films_title_prefix ON films (lower(title) text_pattern_ops);
+
+CREATE INDEX idx_actors_full_name_prefix ON actors (lower(name || ' ' || surname) text_pattern_ops);
+
+CREATE INDEX idx_actors_surname_prefix ON actors (lower(surname) text_pattern_ops);
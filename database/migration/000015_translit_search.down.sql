@@ -0,0 +1,14 @@
+DROP INDEX idx_actors_surname_norm_trgm;
+
+DROP INDEX idx_actors_name_norm_trgm;
+
+DROP INDEX idx_films_title_norm_trgm;
+
+ALTER TABLE actors
+    DROP COLUMN surname_norm,
+    DROP COLUMN name_norm;
+
+ALTER TABLE films
+    DROP COLUMN title_norm;
+
+DROP FUNCTION translit_normalize(text);
@@ -0,0 +1,29 @@
+-- translit_normalize brings Russian and English spellings of a name to one form: the text
+-- is lowercased, ё is read as е, Cyrillic is transliterated to Latin and the endings
+-- of words like -ий and -ый are spelled -y, so "Тарковский" and "Tarkovsky" both give "tarkovsky".
+CREATE FUNCTION translit_normalize(value text) RETURNS text AS
+$$
+SELECT regexp_replace(
+               translate(
+                       replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(
+                           lower(value),
+                           'ё', 'е'), 'щ', 'shch'), 'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'),
+                           'ч', 'ch'), 'ш', 'sh'), 'ю', 'yu'), 'я', 'ya'), 'x', 'ks'),
+                       'абвгдезийклмнопрстуфыэъь', 'abvgdeziyklmnoprstufye'),
+               '(iy|yy|ij)\M', 'y', 'g')
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- The normalised columns are generated, so every insert and update of a name keeps them
+-- up to date.
+ALTER TABLE films
+    ADD COLUMN title_norm text GENERATED ALWAYS AS (translit_normalize(title)) STORED;
+
+ALTER TABLE actors
+    ADD COLUMN name_norm    text GENERATED ALWAYS AS (translit_normalize(name)) STORED,
+    ADD COLUMN surname_norm text GENERATED ALWAYS AS (translit_normalize(surname)) STORED;
+
+CREATE INDEX idx_films_title_norm_trgm ON films USING GIN (title_norm gin_trgm_ops);
+
+CREATE INDEX idx_actors_name_norm_trgm ON actors USING GIN (name_norm gin_trgm_ops);
+
+CREATE INDEX idx_actors_surname_norm_trgm ON actors USING GIN (surname_norm gin_trgm_ops);
@@ -0,0 +1,20 @@
+-- audit_log keeps the changes of films, actors and users. user_id is not a foreign key,
+-- so the entries of a deleted user stay in the log.
+CREATE TABLE audit_log
+(
+    id         serial PRIMARY KEY,
+    user_id    integer     not null,
+    entity     varchar(32) not null,
+    entity_id  integer     not null,
+    action     varchar(16) not null,
+    before     jsonb,
+    after      jsonb,
+    created_at timestamptz not null default now(),
+    CHECK (action = 'create' or action = 'update' or action = 'delete')
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entity, entity_id, created_at DESC);
+
+CREATE INDEX idx_audit_log_user ON audit_log (user_id, created_at DESC);
+
+CREATE INDEX idx_audit_log_created ON audit_log (created_at DESC);
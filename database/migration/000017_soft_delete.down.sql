@@ -0,0 +1,44 @@
+DELETE FROM audit_log WHERE action = 'restore';
+
+ALTER TABLE audit_log
+    DROP CONSTRAINT audit_log_action_check;
+
+ALTER TABLE audit_log
+    ADD CONSTRAINT audit_log_action_check
+        CHECK (action = 'create' or action = 'update' or action = 'delete');
+
+DELETE FROM films WHERE deleted_at IS NOT NULL;
+
+DELETE FROM actors WHERE deleted_at IS NOT NULL;
+
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX idx_films_deleted;
+
+DROP INDEX idx_actors_deleted;
+
+DROP INDEX idx_users_deleted;
+
+DROP INDEX uq_films_title;
+
+DROP INDEX uq_actors_name;
+
+DROP INDEX uq_users_login;
+
+ALTER TABLE films
+    ADD CONSTRAINT films_title_key UNIQUE (title);
+
+ALTER TABLE actors
+    ADD CONSTRAINT uqc_actor_name UNIQUE (name, surname);
+
+ALTER TABLE users
+    ADD CONSTRAINT users_login_key UNIQUE (login);
+
+ALTER TABLE films
+    DROP COLUMN deleted_at;
+
+ALTER TABLE actors
+    DROP COLUMN deleted_at;
+
+ALTER TABLE users
+    DROP COLUMN deleted_at;
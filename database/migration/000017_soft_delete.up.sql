@@ -0,0 +1,41 @@
+-- deleted_at marks the films, actors and users moved to the trash. The rows keep their
+-- links, so a restore brings back the cast of a film as it was, and they are removed for
+-- good only by the purge after the retention period.
+ALTER TABLE films
+    ADD COLUMN deleted_at timestamptz;
+
+ALTER TABLE actors
+    ADD COLUMN deleted_at timestamptz;
+
+ALTER TABLE users
+    ADD COLUMN deleted_at timestamptz;
+
+-- the names stay unique only among the live rows, a deleted film does not block a new one
+-- with its title.
+ALTER TABLE films
+    DROP CONSTRAINT films_title_key;
+
+CREATE UNIQUE INDEX uq_films_title ON films (title) WHERE deleted_at IS NULL;
+
+ALTER TABLE actors
+    DROP CONSTRAINT uqc_actor_name;
+
+CREATE UNIQUE INDEX uq_actors_name ON actors (name, surname) WHERE deleted_at IS NULL;
+
+ALTER TABLE users
+    DROP CONSTRAINT users_login_key;
+
+CREATE UNIQUE INDEX uq_users_login ON users (login) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_films_deleted ON films (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX idx_actors_deleted ON actors (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX idx_users_deleted ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE audit_log
+    DROP CONSTRAINT audit_log_action_check;
+
+ALTER TABLE audit_log
+    ADD CONSTRAINT audit_log_action_check
+        CHECK (action IN ('create', 'update', 'delete', 'restore'));
@@ -0,0 +1,33 @@
+-- revisions keep the versions of films and actors, a film revision holds also its cast and
+-- genres. user_id is null for the first revisions taken from the rows present at migration.
+CREATE TABLE revisions
+(
+    id         serial PRIMARY KEY,
+    entity     varchar(32) not null,
+    entity_id  integer     not null,
+    version    integer     not null,
+    user_id    integer,
+    snapshot   jsonb       not null,
+    created_at timestamptz not null default now(),
+    UNIQUE (entity, entity_id, version)
+);
+
+INSERT INTO revisions (entity, entity_id, version, snapshot)
+SELECT 'film', f.id, 1,
+       (to_jsonb(f) - 'id' - 'search_vector' - 'title_norm' - 'avg_rating' - 'votes' - 'deleted_at') ||
+       jsonb_build_object(
+               'cast', COALESCE((SELECT jsonb_agg(jsonb_build_object('actor_id', s.actor_id,
+                                                                     'character', s.character_name,
+                                                                     'billing', s.billing_order)
+                                                  ORDER BY s.billing_order, s.actor_id)
+                                 FROM starred_in s
+                                 WHERE s.film_id = f.id), '[]'),
+               'genres', COALESCE((SELECT jsonb_agg(g.name ORDER BY g.name)
+                                   FROM film_genres fg
+                                            INNER JOIN genres g ON (fg.genre_id = g.id)
+                                   WHERE fg.film_id = f.id), '[]'))
+FROM films f;
+
+INSERT INTO revisions (entity, entity_id, version, snapshot)
+SELECT 'actor', a.id, 1, to_jsonb(a) - 'id' - 'name_norm' - 'surname_norm' - 'deleted_at'
+FROM actors a;
@@ -0,0 +1,5 @@
+ALTER TABLE revoked_tokens
+    ALTER COLUMN expires_at TYPE timestamp;
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN expires_at TYPE timestamp;
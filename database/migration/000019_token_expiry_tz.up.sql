@@ -0,0 +1,8 @@
+-- the expiry of a token is a moment rather than a wall clock time, so it does not shift
+-- with the time zone of the server. The stored values are read in the zone of the session,
+-- as the comparisons with now() did so far.
+ALTER TABLE refresh_tokens
+    ALTER COLUMN expires_at TYPE timestamptz;
+
+ALTER TABLE revoked_tokens
+    ALTER COLUMN expires_at TYPE timestamptz;
@@ -1,6 +1,7 @@
 package filmoteka
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"strings"
 	"time"
@@ -14,6 +15,11 @@ func (d *Date) String() string {
 	return fmt.Sprintf(time.Time.Format(time.Time(*d), layout))
 }
 
+// Value lets a date be passed to the database as a query argument.
+func (d *Date) Value() (driver.Value, error) {
+	return time.Time(*d), nil
+}
+
 func (d *Date) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + d.String() + `"`), nil
 }
@@ -0,0 +1,12 @@
+package filmoteka
+
+import "time"
+
+// DeletedEntity is a film, an actor or a user in the trash. Name is the title of the
+// film, the full name of the actor or the login of the user.
+type DeletedEntity struct {
+	Entity    string    `json:"entity" db:"entity"`
+	Id        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at"`
+}
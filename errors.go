@@ -0,0 +1,65 @@
+package filmoteka
+
+import "errors"
+
+type ErrorCode string
+
+const (
+	CodeValidation       ErrorCode = "validation_error"
+	CodeUnauthorized     ErrorCode = "unauthorized"
+	CodeForbidden        ErrorCode = "forbidden"
+	CodeNotFound         ErrorCode = "not_found"
+	CodeMethodNotAllowed ErrorCode = "method_not_allowed"
+	CodeConflict         ErrorCode = "conflict"
+	CodeInternal         ErrorCode = "internal_error"
+)
+
+// Error is returned by models_dao and service when the failure is caused by the request
+// itself. Message and Details are safe to show to clients, the cause in Err is not.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Details map[string]string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func ValidationError(message string, details map[string]string) *Error {
+	return &Error{Code: CodeValidation, Message: message, Details: details}
+}
+
+func NotFoundError(message string) *Error {
+	return &Error{Code: CodeNotFound, Message: message}
+}
+
+func ConflictError(message string, details map[string]string) *Error {
+	return &Error{Code: CodeConflict, Message: message, Details: details}
+}
+
+func UnauthorizedError(message string) *Error {
+	return &Error{Code: CodeUnauthorized, Message: message}
+}
+
+func ForbiddenError(message string) *Error {
+	return &Error{Code: CodeForbidden, Message: message}
+}
+
+// ErrorCodeOf returns the code of a typed error in the chain of err or CodeInternal.
+func ErrorCodeOf(err error) ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeInternal
+}
@@ -6,11 +6,22 @@ import (
 )
 
 type Film struct {
-	Id          int    `json:"id" db:"id"`
-	Title       string `json:"title" db:"title"`
-	Description string `json:"description" db:"description"`
-	IssueDate   *Date  `json:"issue_date" db:"issue_date"`
-	Rating      int    `json:"rating" db:"rating"`
+	Id          int     `json:"id" db:"id"`
+	Title       string  `json:"title" db:"title"`
+	Description string  `json:"description" db:"description"`
+	IssueDate   *Date   `json:"issue_date" db:"issue_date"`
+	Rating      int     `json:"rating" db:"rating"`
+	AvgRating   float64 `json:"avg_rating" db:"avg_rating"`
+	Votes       int     `json:"votes" db:"votes"`
+}
+
+// FilmSearchResult is a film found by the search. Full-text search also ranks it and
+// returns the fragments of its title and description with the matched words in <b> tags.
+type FilmSearchResult struct {
+	InputFilm
+	Rank         float64 `json:"rank,omitempty" db:"rank"`
+	TitleSnippet *string `json:"title_snippet,omitempty" db:"title_snippet"`
+	Snippet      *string `json:"snippet,omitempty" db:"snippet"`
 }
 
 func (f *Film) UnmarshalJSON(data []byte) error {
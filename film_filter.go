@@ -0,0 +1,78 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	MatchAll = "all"
+	MatchAny = "any"
+
+	defaultYearBucket = 10
+)
+
+// FilmFilter narrows the film list by ranges of issue year and rating, by genres and cast
+// members that must all (or any of them) be present and by terms, genres and actors that
+// must be absent.
+//
+// The rating range applies to the average rating of the reviews, the one the list is
+// ordered by, rather than the rating set by editors. It is given in whole points like the
+// rating facet: a film rated 7.85 is in the bucket from 7 to 7, so rating_max 7 keeps it
+// and rating_min 8 does not.
+type FilmFilter struct {
+	Title         *string  `json:"title"`
+	YearFrom      *int     `json:"year_from"`
+	YearTo        *int     `json:"year_to"`
+	RatingMin     *int     `json:"rating_min"`
+	RatingMax     *int     `json:"rating_max"`
+	Genres        []string `json:"genres"`
+	GenreMatch    string   `json:"genre_match"`
+	Actors        []int    `json:"actors"`
+	ActorMatch    string   `json:"actor_match"`
+	ExcludeTerms  []string `json:"exclude_terms"`
+	ExcludeGenres []string `json:"exclude_genres"`
+	ExcludeActors []int    `json:"exclude_actors"`
+	YearBucket    int      `json:"year_bucket"`
+}
+
+func (f *FilmFilter) UnmarshalJSON(data []byte) error {
+	type tmp FilmFilter
+	result := tmp{GenreMatch: MatchAll, ActorMatch: MatchAll, YearBucket: defaultYearBucket}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	switch {
+	case result.YearFrom != nil && result.YearTo != nil && *result.YearFrom > *result.YearTo:
+		return errors.New("year_from is after year_to")
+	case result.RatingMin != nil && (*result.RatingMin < 0 || *result.RatingMin > 10),
+		result.RatingMax != nil && (*result.RatingMax < 0 || *result.RatingMax > 10):
+		return errors.New("rating range must be within 0 and 10")
+	case result.RatingMin != nil && result.RatingMax != nil && *result.RatingMin > *result.RatingMax:
+		return errors.New("rating_min is greater than rating_max")
+	case result.GenreMatch != MatchAll && result.GenreMatch != MatchAny,
+		result.ActorMatch != MatchAll && result.ActorMatch != MatchAny:
+		return errors.New("match must be all or any")
+	case result.YearBucket < 1 || result.YearBucket > 100:
+		return errors.New("year_bucket must be within 1 and 100")
+	}
+
+	*f = FilmFilter(result)
+	return nil
+}
+
+// FacetBucket counts the films with the value from From to To inclusive.
+type FacetBucket struct {
+	From  int `json:"from" db:"bucket_from"`
+	To    int `json:"to" db:"bucket_to"`
+	Count int `json:"count" db:"count"`
+}
+
+// Facets count the filtered films by issue year and by rating. The counts of a facet do
+// not apply the range of the facet itself, so they show how many films every other
+// choice of the range would give.
+type Facets struct {
+	Years   []FacetBucket `json:"years"`
+	Ratings []FacetBucket `json:"ratings"`
+}
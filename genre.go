@@ -0,0 +1,32 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+type Genre struct {
+	Id   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+}
+
+func (g *Genre) UnmarshalJSON(data []byte) error {
+	result := struct {
+		Id   *int    `json:"id"`
+		Name *string `json:"name"`
+	}{}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	if result.Name == nil || len(*result.Name) == 0 {
+		return errors.New("invalid state for required field(s)")
+	} else {
+		if result.Id != nil {
+			g.Id = *result.Id
+		}
+		g.Name = *result.Name
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ require (
 	github.com/sirupsen/logrus v1.9.3
 	github.com/stretchr/testify v1.7.0
 	go.uber.org/mock v0.4.0
+	golang.org/x/crypto v0.21.0
 )
 
 require (
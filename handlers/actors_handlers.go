@@ -5,34 +5,31 @@ import (
 	"github.com/jorgini/filmoteka"
 	"github.com/sirupsen/logrus"
 	"net/http"
-	"strconv"
 )
 
-const (
-	limitOnPage = 10
-)
+var actorSortingOption = map[string]struct{}{"name": {}, "surname": {}, "birthday": {}, "film_count": {}}
 
 func createNewActor(r *Router, writer http.ResponseWriter, request *http.Request) {
 	id, err := getUserId(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	var actor filmoteka.Actor
 	if err := parseBody(request.Body, &actor); err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	actorId, err := r.service.Actor.CreateActor(actor)
+	actorId, err := r.service.Actor.CreateActor(id, actor)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	if err = writeBody(writer, fmt.Sprintf("successfully create actor with id %d", actorId)); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 	}
 
 	logrus.Infof("new actor with id %d was created by user with id %d", actorId, id)
@@ -41,11 +38,11 @@ func createNewActor(r *Router, writer http.ResponseWriter, request *http.Request
 func updateActor(r *Router, writer http.ResponseWriter, request *http.Request) {
 	id, err := getUserId(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	actorId, err := strconv.Atoi(request.URL.Query().Get("id"))
+	actorId, err := getIntParam(request, "id")
 	if err != nil {
 		r.sendErrorResponse(writer, http.StatusBadRequest, "no id specified for update actor")
 		return
@@ -53,53 +50,51 @@ func updateActor(r *Router, writer http.ResponseWriter, request *http.Request) {
 
 	var input filmoteka.UpdateActorInput
 	if err = parseBody(request.Body, &input); err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 	input.Id = &actorId
 
-	if err = r.service.Actor.UpdateActor(input); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+	if err = r.service.Actor.UpdateActor(id, input); err != nil {
+		r.sendError(writer, err)
 		return
 	}
 
 	if err = writeBody(writer, "successfully update"); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 	}
 
 	logrus.Infof("actor with id %d has been updated by user with id %d", *input.Id, id)
 }
 
 func getActorsList(r *Router, writer http.ResponseWriter, request *http.Request) {
-	page, err := strconv.Atoi(request.URL.Query().Get("page"))
+	page, size, err := r.getPageParams(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "page not selected")
-		return
-	}
-	if page < 1 {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "page out of bounds")
+		r.sendError(writer, err)
 		return
 	}
 
-	actors, err := r.service.Actor.GetActorsList(page, limitOnPage)
+	sort, err := getSortParam(request, actorSortingOption, "")
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
-	if len(actors) == 0 {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "page out of bounds")
+
+	actors, total, err := r.service.Actor.GetActorsList(sort, page, size)
+	if err != nil {
+		r.sendError(writer, err)
 		return
 	}
 
-	if err := writeBody(writer, actors...); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+	if err := writeBody(writer, newPage(request, actors, page, size, total)); err != nil {
+		r.sendError(writer, err)
 		return
 	}
 	logrus.Infof("list of actors in page %d was sending to user", page)
 }
 
 func getActorById(r *Router, writer http.ResponseWriter, request *http.Request) {
-	actorId, err := strconv.Atoi(request.URL.Query().Get("id"))
+	actorId, err := getIntParam(request, "id")
 	if err != nil {
 		r.sendErrorResponse(writer, http.StatusBadRequest, "id not selected")
 		return
@@ -111,41 +106,38 @@ func getActorById(r *Router, writer http.ResponseWriter, request *http.Request)
 
 	actor, err := r.service.Actor.GetActorById(actorId)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	if err := writeBody(writer, actor); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 	logrus.Infof("actor %s was sent to user", actor.Actor.Surname)
 }
 
 func searchActor(r *Router, writer http.ResponseWriter, request *http.Request) {
-	page, err := strconv.Atoi(request.URL.Query().Get("page"))
+	page, size, err := r.getPageParams(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "page not selected")
+		r.sendError(writer, err)
 		return
 	}
 
 	var fragment filmoteka.ActorSearchFragment
 	if err := parseBody(request.Body, &fragment); err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	actors, err := r.service.Actor.SearchActor(page, limitOnPage, fragment)
+	actors, total, err := r.service.Actor.SearchActor(page, size, fragment)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
-	if len(actors) == 0 {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "nothing find")
-	}
 
-	if err := writeBody(writer, actors...); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+	if err := writeBody(writer, newPage(request, actors, page, size, total)); err != nil {
+		r.sendError(writer, err)
 		return
 	}
 	logrus.Info("result of search actor with fragment name was sending to user")
@@ -154,22 +146,22 @@ func searchActor(r *Router, writer http.ResponseWriter, request *http.Request) {
 func deleteActor(r *Router, writer http.ResponseWriter, request *http.Request) {
 	id, err := getUserId(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	inputId, err := strconv.Atoi(request.URL.Query().Get("id"))
+	inputId, err := getIntParam(request, "id")
 	if err != nil {
 		r.sendErrorResponse(writer, http.StatusBadRequest, "id doesnt specified to delete actor")
 		return
 	}
 
-	if err = r.service.Actor.DeleteActorById(inputId); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+	if err = r.service.Actor.DeleteActorById(id, inputId); err != nil {
+		r.sendError(writer, err)
 		return
 	}
 	if err = writeBody(writer, "successful delete"); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 	}
 
 	logrus.Infof("actor with id %d has been deleted by user with id %d", inputId, id)
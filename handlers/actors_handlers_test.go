@@ -45,8 +45,8 @@ func TestRouter_createNewActor(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.Actor) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().CreateActor(actor).Return(1, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateActor(userId, actor).Return(1, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `"successfully create actor with id 1"`,
@@ -57,10 +57,10 @@ func TestRouter_createNewActor(t *testing.T) {
 			inputActor: filmoteka.Actor{},
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.Actor) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   400,
-			expectedResponseBody: `invalid state for required field(s)`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required field(s)"}`,
 		},
 		{
 			name:      "Locked",
@@ -73,10 +73,10 @@ func TestRouter_createNewActor(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.Actor) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(false, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.RegularRole, nil)
 			},
-			expectedStatusCode:   423,
-			expectedResponseBody: `this function locked for current user`,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
 		},
 		{
 			name:      "Service Error",
@@ -89,11 +89,11 @@ func TestRouter_createNewActor(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.Actor) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().CreateActor(actor).Return(0, errors.New("something went wrong"))
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateActor(userId, actor).Return(0, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -163,8 +163,8 @@ func TestRouter_updateActor(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.UpdateActorInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().UpdateActor(actor).Return(nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().UpdateActor(userId, actor).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `"successfully update"`,
@@ -181,10 +181,10 @@ func TestRouter_updateActor(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.UpdateActorInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   400,
-			expectedResponseBody: `no id specified for update actor`,
+			expectedResponseBody: `{"code":"validation_error","message":"no id specified for update actor"}`,
 		},
 		{
 			name:        "Wrong Input",
@@ -194,10 +194,10 @@ func TestRouter_updateActor(t *testing.T) {
 			inputActor:  filmoteka.UpdateActorInput{},
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.UpdateActorInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   400,
-			expectedResponseBody: `invalid state for required filed id to update actor`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required filed id to update actor"}`,
 		},
 		{
 			name:        "Locked",
@@ -211,10 +211,28 @@ func TestRouter_updateActor(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.UpdateActorInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(false, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.RegularRole, nil)
 			},
-			expectedStatusCode:   423,
-			expectedResponseBody: `this function locked for current user`,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
+		},
+		{
+			name:        "Not Found",
+			paramsName:  "id",
+			paramsValue: "1",
+			inputBody:   `{"surname": "surname", "birthday": "11-08-2023"}`,
+			inputActor: filmoteka.UpdateActorInput{
+				Id:       &actorId,
+				Surname:  &surnameUpdate,
+				Birthday: (*filmoteka.Date)(&date),
+			},
+			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.UpdateActorInput) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().UpdateActor(userId, actor).Return(filmoteka.NotFoundError("actor not found"))
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"not_found","message":"actor not found"}`,
 		},
 		{
 			name:        "Service Error",
@@ -228,11 +246,11 @@ func TestRouter_updateActor(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser, actor filmoteka.UpdateActorInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().UpdateActor(actor).Return(errors.New("something went wrong"))
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().UpdateActor(userId, actor).Return(errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -268,7 +286,7 @@ func TestRouter_updateActor(t *testing.T) {
 
 func TestRouter_getActorsList(t *testing.T) {
 	// Init Test Table
-	type mockBehavior func(r *mock_service.MockActor, page int)
+	type mockBehavior func(r *mock_service.MockActor, sort []filmoteka.SortKey, page int)
 
 	var (
 		date  = time.Time{}.AddDate(2022, 7, 10)
@@ -295,6 +313,7 @@ func TestRouter_getActorsList(t *testing.T) {
 		paramsName           string
 		paramsValue          string
 		page                 int
+		sort                 []filmoteka.SortKey
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -304,51 +323,71 @@ func TestRouter_getActorsList(t *testing.T) {
 			paramsName:  "page",
 			paramsValue: "1",
 			page:        1,
-			mockBehavior: func(r *mock_service.MockActor, page int) {
-				r.EXPECT().GetActorsList(page, limit).Return(actor, nil)
+			mockBehavior: func(r *mock_service.MockActor, sort []filmoteka.SortKey, page int) {
+				r.EXPECT().GetActorsList(sort, page, limit).Return(actor, 1, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"Actor":{"id":0,"name":"test","surname":"test","sex":"female","birthday":"11-08-2023"},"Films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5}]}`,
+			expectedResponseBody: `{"items":[{"Actor":{"id":0,"name":"test","surname":"test","sex":"female","birthday":"11-08-2023"},"Films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0}]}],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:        "Ok sort",
+			paramsName:  "sort",
+			paramsValue: "-film_count,surname",
+			page:        1,
+			sort:        []filmoteka.SortKey{{Column: "film_count", Desc: true}, {Column: "surname"}},
+			mockBehavior: func(r *mock_service.MockActor, sort []filmoteka.SortKey, page int) {
+				r.EXPECT().GetActorsList(sort, page, limit).Return(actor, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[{"Actor":{"id":0,"name":"test","surname":"test","sex":"female","birthday":"11-08-2023"},"Films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0}]}],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:                 "Wrong Sort",
+			paramsName:           "sort",
+			paramsValue:          "-title",
+			mockBehavior:         func(r *mock_service.MockActor, sort []filmoteka.SortKey, page int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid sort","details":{"sort":"unknown column title"}}`,
 		},
 		{
 			name:                 "Wrong Params",
-			paramsName:           "paage",
-			paramsValue:          "1",
+			paramsName:           "page_size",
+			paramsValue:          "many",
 			page:                 1,
-			mockBehavior:         func(r *mock_service.MockActor, page int) {},
+			mockBehavior:         func(r *mock_service.MockActor, sort []filmoteka.SortKey, page int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `page not selected`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid page size","details":{"page_size":"expected positive int"}}`,
 		},
 		{
 			name:                 "Wrong Input",
 			paramsName:           "page",
 			paramsValue:          "-1",
 			page:                 -1,
-			mockBehavior:         func(r *mock_service.MockActor, page int) {},
+			mockBehavior:         func(r *mock_service.MockActor, sort []filmoteka.SortKey, page int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `page out of bounds`,
+			expectedResponseBody: `{"code":"validation_error","message":"page out of bounds"}`,
 		},
 		{
 			name:        "Over page",
 			paramsName:  "page",
 			paramsValue: "2",
 			page:        2,
-			mockBehavior: func(r *mock_service.MockActor, page int) {
-				r.EXPECT().GetActorsList(page, limit).Return([]filmoteka.ActorListItem{}, nil)
+			mockBehavior: func(r *mock_service.MockActor, sort []filmoteka.SortKey, page int) {
+				r.EXPECT().GetActorsList(sort, page, limit).Return(nil, 1, nil)
 			},
-			expectedStatusCode:   400,
-			expectedResponseBody: `page out of bounds`,
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/actors/list?page=1\u0026page_size=10"}`,
 		},
 		{
 			name:        "Service Error",
 			paramsName:  "page",
 			paramsValue: "1",
 			page:        1,
-			mockBehavior: func(r *mock_service.MockActor, page int) {
-				r.EXPECT().GetActorsList(page, limit).Return(nil, errors.New("something went wrong"))
+			mockBehavior: func(r *mock_service.MockActor, sort []filmoteka.SortKey, page int) {
+				r.EXPECT().GetActorsList(sort, page, limit).Return(nil, 0, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -359,7 +398,7 @@ func TestRouter_getActorsList(t *testing.T) {
 			defer c.Finish()
 
 			repo := mock_service.NewMockActor(c)
-			test.mockBehavior(repo, test.page)
+			test.mockBehavior(repo, test.sort, test.page)
 
 			services := &service.Service{Actor: repo}
 			handler := Router{service: services}
@@ -403,6 +442,17 @@ func TestRouter_getActorById(t *testing.T) {
 				Rating:      5,
 			}},
 		}
+		credited = filmoteka.ActorListItem{
+			Actor: actor.Actor,
+			Films: actor.Films,
+			Credits: filmoteka.FilmCredits{"director": {{
+				Id:          2,
+				Title:       "test2",
+				Description: "",
+				IssueDate:   (*filmoteka.Date)(&date),
+				Rating:      7,
+			}}},
+		}
 	)
 
 	tests := []struct {
@@ -421,7 +471,17 @@ func TestRouter_getActorById(t *testing.T) {
 				r.EXPECT().GetActorById(actorId).Return(actor, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"Actor":{"id":1,"name":"test","surname":"test","sex":"female","birthday":"11-08-2023"},"Films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5}]}`,
+			expectedResponseBody: `{"Actor":{"id":1,"name":"test","surname":"test","sex":"female","birthday":"11-08-2023"},"Films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0}]}`,
+		},
+		{
+			name:        "Ok with credits",
+			paramsName:  "id",
+			paramsValue: "1",
+			mockBehavior: func(r *mock_service.MockActor) {
+				r.EXPECT().GetActorById(actorId).Return(credited, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"Actor":{"id":1,"name":"test","surname":"test","sex":"female","birthday":"11-08-2023"},"Films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0}],"Credits":{"director":[{"id":2,"title":"test2","description":"","issue_date":"11-08-2023","rating":7,"avg_rating":0,"votes":0}]}}`,
 		},
 		{
 			name:                 "Wrong Params",
@@ -429,7 +489,7 @@ func TestRouter_getActorById(t *testing.T) {
 			paramsValue:          "1",
 			mockBehavior:         func(r *mock_service.MockActor) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `id not selected`,
+			expectedResponseBody: `{"code":"validation_error","message":"id not selected"}`,
 		},
 		{
 			name:                 "Wrong Input",
@@ -437,7 +497,7 @@ func TestRouter_getActorById(t *testing.T) {
 			paramsValue:          "-1",
 			mockBehavior:         func(r *mock_service.MockActor) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `id out of bounds`,
+			expectedResponseBody: `{"code":"validation_error","message":"id out of bounds"}`,
 		},
 		{
 			name:        "Service Error",
@@ -447,7 +507,7 @@ func TestRouter_getActorById(t *testing.T) {
 				r.EXPECT().GetActorById(actorId).Return(filmoteka.ActorListItem{}, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -527,19 +587,36 @@ func TestRouter_searchActor(t *testing.T) {
 				Surname: &surname,
 			},
 			mockBehavior: func(r *mock_service.MockActor, fragment filmoteka.ActorSearchFragment, page int) {
-				r.EXPECT().SearchActor(page, limit, fragment).Return(actor, nil)
+				r.EXPECT().SearchActor(page, limit, fragment).Return(actor, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[{"Actor":{"id":0,"name":"test","surname":"test","sex":"female","birthday":"11-08-2023"},"Films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0}]}],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:        "Ok fuzzy",
+			paramsName:  "page",
+			paramsValue: "1",
+			page:        1,
+			inputBody:   `{"name":"te", "surname": "t", "fuzzy": true}`,
+			fragment: filmoteka.ActorSearchFragment{
+				Name:    &name,
+				Surname: &surname,
+				Fuzzy:   true,
+			},
+			mockBehavior: func(r *mock_service.MockActor, fragment filmoteka.ActorSearchFragment, page int) {
+				r.EXPECT().SearchActor(page, limit, fragment).Return(actor, 1, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"Actor":{"id":0,"name":"test","surname":"test","sex":"female","birthday":"11-08-2023"},"Films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5}]}`,
+			expectedResponseBody: `{"items":[{"Actor":{"id":0,"name":"test","surname":"test","sex":"female","birthday":"11-08-2023"},"Films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0}]}],"page":1,"page_size":10,"total":1}`,
 		},
 		{
 			name:                 "Wrong Params",
-			paramsName:           "paage",
-			paramsValue:          "1",
+			paramsName:           "page_size",
+			paramsValue:          "many",
 			page:                 1,
 			mockBehavior:         func(r *mock_service.MockActor, fragment filmoteka.ActorSearchFragment, page int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `page not selected`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid page size","details":{"page_size":"expected positive int"}}`,
 		},
 		{
 			name:        "Wrong Input",
@@ -553,23 +630,23 @@ func TestRouter_searchActor(t *testing.T) {
 			},
 			mockBehavior:         func(r *mock_service.MockActor, fragment filmoteka.ActorSearchFragment, page int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `parameters for search not specified`,
+			expectedResponseBody: `{"code":"validation_error","message":"parameters for search not specified"}`,
 		},
 		{
-			name:        "Bad request",
+			name:        "Over page",
 			paramsName:  "page",
-			paramsValue: "1",
-			page:        1,
+			paramsValue: "2",
+			page:        2,
 			inputBody:   `{"name":"te", "surname": "t"}`,
 			fragment: filmoteka.ActorSearchFragment{
 				Name:    &name,
 				Surname: &surname,
 			},
 			mockBehavior: func(r *mock_service.MockActor, fragment filmoteka.ActorSearchFragment, page int) {
-				r.EXPECT().SearchActor(page, limit, fragment).Return([]filmoteka.ActorListItem{}, nil)
+				r.EXPECT().SearchActor(page, limit, fragment).Return([]filmoteka.ActorListItem{}, 1, nil)
 			},
-			expectedStatusCode:   400,
-			expectedResponseBody: `nothing find`,
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/actors/search?page=1\u0026page_size=10"}`,
 		},
 		{
 			name:        "Service Error",
@@ -582,10 +659,10 @@ func TestRouter_searchActor(t *testing.T) {
 				Surname: &surname,
 			},
 			mockBehavior: func(r *mock_service.MockActor, fragment filmoteka.ActorSearchFragment, page int) {
-				r.EXPECT().SearchActor(page, limit, fragment).Return(nil, errors.New("something went wrong"))
+				r.EXPECT().SearchActor(page, limit, fragment).Return(nil, 0, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -626,7 +703,7 @@ func TestRouter_deleteActor(t *testing.T) {
 		headerValue = "Bearer test"
 		token       = "test"
 		userId      = 1
-		actorId     = 1
+		actorId     = 3
 	)
 
 	tests := []struct {
@@ -640,11 +717,11 @@ func TestRouter_deleteActor(t *testing.T) {
 		{
 			name:        "Ok",
 			paramsName:  "id",
-			paramsValue: "1",
+			paramsValue: "3",
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().DeleteActorById(actorId).Return(nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().DeleteActorById(userId, actorId).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `"successful delete"`,
@@ -655,33 +732,33 @@ func TestRouter_deleteActor(t *testing.T) {
 			paramsValue: "fafmek",
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   400,
-			expectedResponseBody: `id doesnt specified to delete actor`,
+			expectedResponseBody: `{"code":"validation_error","message":"id doesnt specified to delete actor"}`,
 		},
 		{
 			name:        "Locked",
 			paramsName:  "id",
-			paramsValue: "1",
+			paramsValue: "3",
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(false, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.RegularRole, nil)
 			},
-			expectedStatusCode:   423,
-			expectedResponseBody: `this function locked for current user`,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
 		},
 		{
 			name:        "Service Error",
 			paramsName:  "id",
-			paramsValue: "1",
+			paramsValue: "3",
 			mockBehavior: func(r1 *mock_service.MockActor, r2 *mock_service.MockUser) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().DeleteActorById(actorId).Return(errors.New("something went wrong"))
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().DeleteActorById(userId, actorId).Return(errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
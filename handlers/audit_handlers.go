@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"github.com/jorgini/filmoteka"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var auditEntities = map[string]struct{}{filmoteka.AuditFilm: {}, filmoteka.AuditActor: {}, filmoteka.AuditUser: {}}
+
+// getAuditFilter reads the filter of the audit log from the query: the entity with its
+// id, the author and the time range in RFC 3339.
+func getAuditFilter(request *http.Request) (filmoteka.AuditFilter, error) {
+	var filter filmoteka.AuditFilter
+	var err error
+	query := request.URL.Query()
+
+	if value := query.Get("entity"); value != "" {
+		if _, ok := auditEntities[value]; !ok {
+			return filter, filmoteka.ValidationError("invalid entity",
+				map[string]string{"entity": "expected film, actor or user"})
+		}
+		filter.Entity = &value
+	}
+
+	if filter.EntityId, err = getIdQuery(query, "entity_id"); err != nil {
+		return filter, err
+	}
+	if filter.UserId, err = getIdQuery(query, "user_id"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = getTimeQuery(query, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = getTimeQuery(query, "to"); err != nil {
+		return filter, err
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, filmoteka.ValidationError("empty time range", nil)
+	}
+	return filter, nil
+}
+
+func getIdQuery(query url.Values, name string) (*int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 1 {
+		return nil, filmoteka.ValidationError("invalid "+name, map[string]string{name: "expected positive int"})
+	}
+	return &id, nil
+}
+
+func getTimeQuery(query url.Values, name string) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	moment, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, filmoteka.ValidationError("invalid "+name, map[string]string{name: "expected RFC 3339 time"})
+	}
+	return &moment, nil
+}
+
+func getAuditLog(r *Router, writer http.ResponseWriter, request *http.Request) {
+	page, size, err := r.getPageParams(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	filter, err := getAuditFilter(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	entries, total, err := r.service.Audit.GetAuditLog(filter, page, size)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, newPage(request, entries, page, size, total)); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("audit log in page %d was sent to admin", page)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/service"
+	"github.com/jorgini/filmoteka/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter_getAuditLog(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockAudit, r2 *mock_service.MockUser, filter filmoteka.AuditFilter)
+
+	const token = "fmekwfmw"
+
+	var (
+		entity   = filmoteka.AuditFilm
+		entityId = 3
+		userId   = 2
+		from     = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to       = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		entries  = []filmoteka.AuditEntry{{
+			Id:        1,
+			UserId:    2,
+			Entity:    filmoteka.AuditFilm,
+			EntityId:  3,
+			Action:    filmoteka.AuditUpdate,
+			Before:    json.RawMessage(`{"id":3,"title":"old"}`),
+			After:     json.RawMessage(`{"id":3,"title":"new"}`),
+			CreatedAt: from,
+		}}
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		filter               filmoteka.AuditFilter
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "entity=film&entity_id=3&user_id=2&from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z",
+			filter: filmoteka.AuditFilter{Entity: &entity, EntityId: &entityId, UserId: &userId, From: &from, To: &to},
+			mockBehavior: func(r1 *mock_service.MockAudit, r2 *mock_service.MockUser, filter filmoteka.AuditFilter) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().GetAuditLog(filter, 1, 10).Return(entries, 1, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[{"id":1,"user_id":2,"entity":"film","entity_id":3,"action":"update",` +
+				`"before":{"id":3,"title":"old"},"after":{"id":3,"title":"new"},"created_at":"2024-01-01T00:00:00Z"}],` +
+				`"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:   "Ok no filter",
+			params: "page=2",
+			mockBehavior: func(r1 *mock_service.MockAudit, r2 *mock_service.MockUser, filter filmoteka.AuditFilter) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().GetAuditLog(filter, 2, 10).Return(nil, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/audit?page=1\u0026page_size=10"}`,
+		},
+		{
+			name:   "Locked",
+			params: "entity=film",
+			mockBehavior: func(r1 *mock_service.MockAudit, r2 *mock_service.MockUser, filter filmoteka.AuditFilter) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.ModeratorRole, nil)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
+		},
+		{
+			name:   "Wrong Entity",
+			params: "entity=review",
+			mockBehavior: func(r1 *mock_service.MockAudit, r2 *mock_service.MockUser, filter filmoteka.AuditFilter) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid entity","details":{"entity":"expected film, actor or user"}}`,
+		},
+		{
+			name:   "Wrong User Id",
+			params: "user_id=me",
+			mockBehavior: func(r1 *mock_service.MockAudit, r2 *mock_service.MockUser, filter filmoteka.AuditFilter) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid user_id","details":{"user_id":"expected positive int"}}`,
+		},
+		{
+			name:   "Wrong Time",
+			params: "from=yesterday",
+			mockBehavior: func(r1 *mock_service.MockAudit, r2 *mock_service.MockUser, filter filmoteka.AuditFilter) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid from","details":{"from":"expected RFC 3339 time"}}`,
+		},
+		{
+			name:   "Empty Time Range",
+			params: "from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z",
+			mockBehavior: func(r1 *mock_service.MockAudit, r2 *mock_service.MockUser, filter filmoteka.AuditFilter) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"empty time range"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "",
+			mockBehavior: func(r1 *mock_service.MockAudit, r2 *mock_service.MockUser, filter filmoteka.AuditFilter) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().GetAuditLog(filter, 1, 10).Return(nil, 0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockAudit(c)
+			user := mock_service.NewMockUser(c)
+			test.mockBehavior(repo, user, test.filter)
+
+			services := &service.Service{Audit: repo, User: user}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/audit", getAuditLog)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/audit?"+test.params, bytes.NewBufferString(""))
+			req.Header.Set(authorizationHeader, fmt.Sprintf("Bearer %s", token))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+func createNewCollection(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	var collection filmoteka.InputCollection
+	if err := parseBody(request.Body, &collection); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	collection.OwnerId = id
+
+	collectionId, err := r.service.Collection.CreateCollection(collection)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, fmt.Sprintf("successfully create collection with id %d", collectionId)); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("new collection with id %d was created by user with id %d", collectionId, id)
+}
+
+func updateCollection(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	collectionId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "no id specified to update collection")
+		return
+	}
+
+	var update filmoteka.UpdateCollectionInput
+	if err = parseBody(request.Body, &update); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	update.Id = &collectionId
+
+	if err = r.service.Collection.UpdateCollection(id, update); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully update"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("collection with id %d was updated by user with id %d", collectionId, id)
+}
+
+// getCollection serves both the public and the authenticated route, so the user id
+// is optional here and private collections are only shown to their owner.
+func getCollection(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, _ := getUserId(request)
+
+	collectionId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id for get collection not specified")
+		return
+	}
+	if collectionId < 1 {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id out of bounds")
+		return
+	}
+
+	collection, err := r.service.Collection.GetCollection(collectionId, id)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, collection); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("collection with id %d was sent to user", collectionId)
+}
+
+func getPublicCollections(r *Router, writer http.ResponseWriter, request *http.Request) {
+	page, size, err := r.getPageParams(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	collections, total, err := r.service.Collection.GetPublicCollections(page, size)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, newPage(request, collections, page, size, total)); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("list of public collections in page %d was sent to user", page)
+}
+
+func getUserCollections(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	page, size, err := r.getPageParams(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	collections, total, err := r.service.Collection.GetUserCollections(id, page, size)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, newPage(request, collections, page, size, total)); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("collections of user with id %d were sent", id)
+}
+
+func deleteCollection(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	collectionId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id doesnt specified to delete collection")
+		return
+	}
+
+	if err = r.service.Collection.DeleteCollection(collectionId, id); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully delete"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("collection with id %d was deleted by user with id %d", collectionId, id)
+}
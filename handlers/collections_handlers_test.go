@@ -0,0 +1,582 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/service"
+	"github.com/jorgini/filmoteka/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter_createNewCollection(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser, collection filmoteka.InputCollection)
+
+	var (
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+	)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		inputCollection      filmoteka.InputCollection
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"title": "Best of 90s noir", "description": "test", "public": true, "films": [3, 1]}`,
+			inputCollection: filmoteka.InputCollection{
+				Collection: filmoteka.Collection{
+					OwnerId:     userId,
+					Title:       "Best of 90s noir",
+					Description: "test",
+					IsPublic:    true,
+				},
+				Films: []int{3, 1},
+			},
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser, collection filmoteka.InputCollection) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().CreateCollection(collection).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully create collection with id 1"`,
+		},
+		{
+			name:            "Wrong Input",
+			inputBody:       `{"description": "test"}`,
+			inputCollection: filmoteka.InputCollection{},
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser, collection filmoteka.InputCollection) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required field(s)"}`,
+		},
+		{
+			name:      "Service Error",
+			inputBody: `{"title": "noir"}`,
+			inputCollection: filmoteka.InputCollection{
+				Collection: filmoteka.Collection{OwnerId: userId, Title: "noir"},
+			},
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser, collection filmoteka.InputCollection) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().CreateCollection(collection).Return(0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockCollection(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2, test.inputCollection)
+
+			services := &service.Service{Collection: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("POST", "/collections", createNewCollection)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/collections",
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_updateCollection(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser, collection filmoteka.UpdateCollectionInput)
+
+	var (
+		headerName   = "Authorization"
+		headerValue  = "Bearer test"
+		token        = "test"
+		userId       = 1
+		collectionId = 1
+		public       = false
+		films        = []int{2}
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		inputBody            string
+		inputCollection      filmoteka.UpdateCollectionInput
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:            "Ok",
+			params:          "id=1",
+			inputBody:       `{"public": false, "films": [2]}`,
+			inputCollection: filmoteka.UpdateCollectionInput{Id: &collectionId, IsPublic: &public, Films: &films},
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser, collection filmoteka.UpdateCollectionInput) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().UpdateCollection(userId, collection).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully update"`,
+		},
+		{
+			name:            "Wrong Input",
+			params:          "id=1",
+			inputBody:       `{}`,
+			inputCollection: filmoteka.UpdateCollectionInput{},
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser, collection filmoteka.UpdateCollectionInput) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required filed to update collection"}`,
+		},
+		{
+			name:            "Service Error",
+			params:          "id=1",
+			inputBody:       `{"public": false, "films": [2]}`,
+			inputCollection: filmoteka.UpdateCollectionInput{Id: &collectionId, IsPublic: &public, Films: &films},
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser, collection filmoteka.UpdateCollectionInput) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().UpdateCollection(userId, collection).Return(errors.New("collection not found for current user"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockCollection(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2, test.inputCollection)
+
+			services := &service.Service{Collection: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("PUT", "/collections", updateCollection)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/collections?"+test.params,
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_getCollection(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser)
+
+	var (
+		createdAt  = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		date       = time.Time{}.AddDate(2022, 7, 10)
+		token      = "test"
+		userId     = 1
+		collection = filmoteka.CollectionListItem{
+			Collection: filmoteka.Collection{
+				Id:        1,
+				OwnerId:   2,
+				Title:     "noir",
+				IsPublic:  true,
+				CreatedAt: createdAt,
+			},
+			Films: []filmoteka.Film{{
+				Id:        1,
+				Title:     "test",
+				IssueDate: (*filmoteka.Date)(&date),
+				Rating:    5,
+			}},
+		}
+	)
+
+	tests := []struct {
+		name                 string
+		route                string
+		headerValue          string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok anonymous",
+			route:  "/collections/public",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r1.EXPECT().GetCollection(1, 0).Return(collection, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1,"owner_id":2,"title":"noir","description":"","public":true,"created_at":"2024-03-01T12:00:00Z","films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0}]}`,
+		},
+		{
+			name:        "Ok owner",
+			route:       "/collections",
+			headerValue: "Bearer test",
+			params:      "id=1",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().GetCollection(1, userId).Return(collection, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1,"owner_id":2,"title":"noir","description":"","public":true,"created_at":"2024-03-01T12:00:00Z","films":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0}]}`,
+		},
+		{
+			name:                 "Wrong Params",
+			route:                "/collections/public",
+			params:               "idd=1",
+			mockBehavior:         func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"id for get collection not specified"}`,
+		},
+		{
+			name:   "Private",
+			route:  "/collections/public",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r1.EXPECT().GetCollection(1, 0).Return(filmoteka.CollectionListItem{},
+					errors.New("collection not found for current user"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockCollection(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2)
+
+			services := &service.Service{Collection: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", test.route, getCollection)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", test.route+"?"+test.params, bytes.NewBufferString(""))
+			if test.headerValue != "" {
+				req.Header.Set("Authorization", test.headerValue)
+			}
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_getPublicCollections(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockCollection)
+
+	var (
+		createdAt   = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		limit       = 10
+		collections = []filmoteka.Collection{{
+			Id:        1,
+			OwnerId:   2,
+			Title:     "noir",
+			IsPublic:  true,
+			CreatedAt: createdAt,
+		}}
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "page=1",
+			mockBehavior: func(r *mock_service.MockCollection) {
+				r.EXPECT().GetPublicCollections(1, limit).Return(collections, 1, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[{"id":1,"owner_id":2,"title":"noir","description":"","public":true,` +
+				`"created_at":"2024-03-01T12:00:00Z"}],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:   "Default Page",
+			params: "page_size=1",
+			mockBehavior: func(r *mock_service.MockCollection) {
+				r.EXPECT().GetPublicCollections(1, 1).Return(collections, 3, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[{"id":1,"owner_id":2,"title":"noir","description":"","public":true,` +
+				`"created_at":"2024-03-01T12:00:00Z"}],"page":1,"page_size":1,"total":3,` +
+				`"next":"/collections/list?page=2\u0026page_size=1"}`,
+		},
+		{
+			name:                 "Wrong Params",
+			params:               "page=first",
+			mockBehavior:         func(r *mock_service.MockCollection) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid page number","details":{"page":"expected int"}}`,
+		},
+		{
+			name:   "Over page",
+			params: "page=2",
+			mockBehavior: func(r *mock_service.MockCollection) {
+				r.EXPECT().GetPublicCollections(2, limit).Return([]filmoteka.Collection{}, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/collections/list?page=1\u0026page_size=10"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "page=1",
+			mockBehavior: func(r *mock_service.MockCollection) {
+				r.EXPECT().GetPublicCollections(1, limit).Return(nil, 0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockCollection(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{Collection: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/collections/list", getPublicCollections)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/collections/list?"+test.params, bytes.NewBufferString(""))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_getUserCollections(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser)
+
+	var (
+		createdAt   = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 2
+		limit       = 10
+		collections = []filmoteka.Collection{{
+			Id:        1,
+			OwnerId:   2,
+			Title:     "noir",
+			CreatedAt: createdAt,
+		}}
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "page=1",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().GetUserCollections(userId, 1, limit).Return(collections, 1, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[{"id":1,"owner_id":2,"title":"noir","description":"","public":false,` +
+				`"created_at":"2024-03-01T12:00:00Z"}],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:   "Empty",
+			params: "",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().GetUserCollections(userId, 1, limit).Return(nil, 0, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":1,"page_size":10,"total":0}`,
+		},
+		{
+			name:   "Wrong Page",
+			params: "page=0",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"page out of bounds"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "page=1",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().GetUserCollections(userId, 1, limit).Return(nil, 0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockCollection(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2)
+
+			services := &service.Service{Collection: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/collections/mine", getUserCollections)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/collections/mine?"+test.params, bytes.NewBufferString(""))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_deleteCollection(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser)
+
+	var (
+		headerName   = "Authorization"
+		headerValue  = "Bearer test"
+		token        = "test"
+		userId       = 1
+		collectionId = 1
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().DeleteCollection(collectionId, userId).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully delete"`,
+		},
+		{
+			name:   "Wrong Params",
+			params: "id=fksfm",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"id doesnt specified to delete collection"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockCollection, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().DeleteCollection(collectionId, userId).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockCollection(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2)
+
+			services := &service.Service{Collection: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("DELETE", "/collections", deleteCollection)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/collections?"+test.params,
+				bytes.NewBufferString(""))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
@@ -8,31 +8,33 @@ import (
 	"strconv"
 )
 
+const defaultFilmSort = "-avg_rating"
+
 var (
-	sortingOption = map[string]struct{}{"title": {}, "rating": {}, "issue_date": {}}
+	sortingOption = map[string]struct{}{"title": {}, "rating": {}, "issue_date": {}, "avg_rating": {}}
 )
 
 func createNewFilm(r *Router, writer http.ResponseWriter, request *http.Request) {
 	id, err := getUserId(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	var film filmoteka.InputFilm
 	if err := parseBody(request.Body, &film); err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	filmId, err := r.service.Film.CreateFilm(film)
+	filmId, err := r.service.Film.CreateFilm(id, film)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	if err = writeBody(writer, fmt.Sprintf("successfully create film with id %d", filmId)); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 	}
 
 	logrus.Infof("new film with id %d was created by user with id %d", filmId, id)
@@ -41,11 +43,11 @@ func createNewFilm(r *Router, writer http.ResponseWriter, request *http.Request)
 func updateFilm(r *Router, writer http.ResponseWriter, request *http.Request) {
 	id, err := getUserId(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	filmId, err := strconv.Atoi(request.URL.Query().Get("id"))
+	filmId, err := getIntParam(request, "id")
 	if err != nil {
 		r.sendErrorResponse(writer, http.StatusBadRequest, "no id specified to update film")
 		return
@@ -53,61 +55,92 @@ func updateFilm(r *Router, writer http.ResponseWriter, request *http.Request) {
 
 	var update filmoteka.UpdateFilmInput
 	if err = parseBody(request.Body, &update); err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 	update.Id = &filmId
 
-	if err = r.service.Film.UpdateFilm(update); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+	if err = r.service.Film.UpdateFilm(id, update); err != nil {
+		r.sendError(writer, err)
 		return
 	}
 
 	if err = writeBody(writer, "successfully update"); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 	}
 
 	logrus.Infof("film with id %d was updated by user with id %d", filmId, id)
 }
 
 func getSortedFilmList(r *Router, writer http.ResponseWriter, request *http.Request) {
-	sort := request.URL.Query().Get("sort_by")
-	if sort == "" {
-		sort = "rating"
-	} else if _, ok := sortingOption[sort]; !ok {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "invalid parameter to sort films list")
-		return
+	// sort_by of a single column is still accepted and sorts in the descending order
+	def := defaultFilmSort
+	if sortBy := request.URL.Query().Get("sort_by"); sortBy != "" {
+		if _, ok := sortingOption[sortBy]; !ok {
+			r.sendErrorResponse(writer, http.StatusBadRequest, "invalid parameter to sort films list")
+			return
+		}
+		def = "-" + sortBy
 	}
 
-	page, err := strconv.Atoi(request.URL.Query().Get("page"))
+	sort, err := getSortParam(request, sortingOption, def)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "no page specified for sorted list")
+		r.sendError(writer, err)
 		return
 	}
-	if page < 0 {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "page out of bounds")
+
+	page, size, err := r.getPageParams(request)
+	if err != nil {
+		r.sendError(writer, err)
 		return
 	}
 
-	films, err := r.service.Film.GetSortedFilmList(sort, page, limitOnPage)
+	var after int
+	if value := request.URL.Query().Get("after"); value != "" {
+		if after, err = strconv.Atoi(value); err != nil || after < 1 {
+			r.sendErrorResponse(writer, http.StatusBadRequest, "invalid film id to continue the list after")
+			return
+		}
+		if request.URL.Query().Has("page") {
+			r.sendError(writer, filmoteka.ValidationError("page cannot be combined with after",
+				map[string]string{"page": "not allowed with after"}))
+			return
+		}
+	}
+
+	var genre *string
+	if value := request.URL.Query().Get("genre"); value != "" {
+		genre = &value
+	}
+
+	films, total, err := r.service.Film.GetSortedFilmList(sort, genre, after, page, size)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
-	if len(films) == 0 {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "page out of bounds")
-		return
+
+	result := newPage(request, films, page, size, total)
+	more := result.Next != ""
+	if after != 0 {
+		// the position of a continued list is unknown, so it has no page number and no link back
+		result.Page, result.Prev = 0, ""
+		more = len(films) == size
+	}
+	if more && len(films) > 0 {
+		result.Next = pageLink(request, 0, size, films[len(films)-1].Id)
+	} else {
+		result.Next = ""
 	}
 
-	if err := writeBody(writer, films); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+	if err := writeBody(writer, result); err != nil {
+		r.sendError(writer, err)
 		return
 	}
-	logrus.Infof("sorted by %s list of films was sent to user", sort)
+	logrus.Infof("sorted by %v list of films was sent to user", sort)
 }
 
 func getCurrentFilm(r *Router, writer http.ResponseWriter, request *http.Request) {
-	id, err := strconv.Atoi(request.URL.Query().Get("id"))
+	id, err := getIntParam(request, "id")
 	if err != nil {
 		r.sendErrorResponse(writer, http.StatusBadRequest, "id for get film not specified")
 		return
@@ -119,67 +152,119 @@ func getCurrentFilm(r *Router, writer http.ResponseWriter, request *http.Request
 
 	film, err := r.service.Film.GetCurFilm(id)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	if err := writeBody(writer, film); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 	logrus.Infof("film with id %d was sent to user", id)
 }
 
+func getFilmCast(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id for get film not specified")
+		return
+	}
+	if id < 1 {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id out of bounds")
+		return
+	}
+
+	cast, err := r.service.Film.GetFilmCast(id)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, cast); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("cast of film with id %d was sent to user", id)
+}
+
 func getSearchFilmList(r *Router, writer http.ResponseWriter, request *http.Request) {
-	page, err := strconv.Atoi(request.URL.Query().Get("page"))
+	page, size, err := r.getPageParams(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "no page specified for search list")
+		r.sendError(writer, err)
 		return
 	}
 
 	var input filmoteka.FilmSearchFragment
 	if err := parseBody(request.Body, &input); err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	films, err := r.service.Film.GetSearchFilmList(page, limitOnPage, input)
+	films, total, err := r.service.Film.GetSearchFilmList(page, size, input)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
-	if len(films) == 0 {
-		r.sendErrorResponse(writer, http.StatusBadRequest, "nothing find")
+
+	if err := writeBody(writer, newPage(request, films, page, size, total)); err != nil {
+		r.sendError(writer, err)
 		return
 	}
+	logrus.Info("search list of films was sent to user")
+}
+
+// facetedPage is the list envelope of the filtered films with their facet counts.
+type facetedPage struct {
+	filmoteka.Page[filmoteka.InputFilm]
+	Facets filmoteka.Facets `json:"facets"`
+}
 
-	if err := writeBody(writer, films); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+func getFilteredFilmList(r *Router, writer http.ResponseWriter, request *http.Request) {
+	page, size, err := r.getPageParams(request)
+	if err != nil {
+		r.sendError(writer, err)
 		return
 	}
-	logrus.Info("search list of films was sent to user")
+
+	var input filmoteka.FilmFilter
+	if err := parseBody(request.Body, &input); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	films, total, facets, err := r.service.Film.GetFilteredFilmList(page, size, input)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, facetedPage{newPage(request, films, page, size, total), facets}); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Info("filtered list of films was sent to user")
 }
 
 func deleteFilm(r *Router, writer http.ResponseWriter, request *http.Request) {
 	id, err := getUserId(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	filmId, err := strconv.Atoi(request.URL.Query().Get("id"))
+	filmId, err := getIntParam(request, "id")
 	if err != nil {
 		r.sendErrorResponse(writer, http.StatusBadRequest, "id doesnt specified to delete film")
 		return
 	}
 
-	if err = r.service.Film.DeleteFilmById(filmId); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+	if err = r.service.Film.DeleteFilmById(id, filmId); err != nil {
+		r.sendError(writer, err)
 		return
 	}
 
 	if err = writeBody(writer, "successfully delete"); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 	}
 
 	logrus.Infof("film with id %d was deleted by user with id %d", filmId, id)
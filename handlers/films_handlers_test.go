@@ -48,8 +48,8 @@ func TestRouter_createNewFilm(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().CreateFilm(film).Return(1, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateFilm(userId, film).Return(1, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `"successfully create film with id 1"`,
@@ -68,22 +68,84 @@ func TestRouter_createNewFilm(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().CreateFilm(film).Return(1, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateFilm(userId, film).Return(1, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `"successfully create film with id 1"`,
 		},
+		{
+			name:      "Ok with characters",
+			inputBody: `{"title": "title", "description": "test", "issue_date": "11-08-2023", "rating" : 5, "Cast": [{"name": "name", "surname":"surname", "character": "hero", "billing": 2}]}`,
+			inputFilm: filmoteka.InputFilm{
+				Film: filmoteka.Film{
+					Title:       "title",
+					Description: "test",
+					IssueDate:   (*filmoteka.Date)(&date),
+					Rating:      5,
+				},
+				Cast: filmoteka.Cast{filmoteka.InputActor{Name: "name", Surname: "surname", Character: "hero", Billing: 2}},
+			},
+			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateFilm(userId, film).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully create film with id 1"`,
+		},
+		{
+			name:      "Wrong Billing",
+			inputBody: `{"title": "title", "issue_date": "11-08-2023", "rating" : 5, "Cast": [{"name": "name", "surname":"surname", "billing": 0}]}`,
+			inputFilm: filmoteka.InputFilm{},
+			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required filed(s)"}`,
+		},
+		{
+			name:      "Ok with credits",
+			inputBody: `{"title": "title", "description": "test", "issue_date": "11-08-2023", "rating" : 5, "credits": {"director": [{"name": "name", "surname":"surname"}]}}`,
+			inputFilm: filmoteka.InputFilm{
+				Film: filmoteka.Film{
+					Title:       "title",
+					Description: "test",
+					IssueDate:   (*filmoteka.Date)(&date),
+					Rating:      5,
+				},
+				Credits: filmoteka.Credits{"director": {{Name: "name", Surname: "surname"}}},
+			},
+			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateFilm(userId, film).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully create film with id 1"`,
+		},
+		{
+			name:      "Wrong Credit Role",
+			inputBody: `{"title": "title", "issue_date": "11-08-2023", "rating" : 5, "credits": {"grip": [{"name": "name", "surname":"surname"}]}}`,
+			inputFilm: filmoteka.InputFilm{},
+			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid role for credit: grip"}`,
+		},
 		{
 			name:      "Wrong Input",
 			inputBody: `{"title": "title"}`,
 			inputFilm: filmoteka.InputFilm{},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   400,
-			expectedResponseBody: `invalid state for required field(s)`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required field(s)"}`,
 		},
 		{
 			name:      "Locked",
@@ -99,10 +161,10 @@ func TestRouter_createNewFilm(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(false, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.RegularRole, nil)
 			},
-			expectedStatusCode:   423,
-			expectedResponseBody: `this function locked for current user`,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
 		},
 		{
 			name:      "Service Error",
@@ -118,11 +180,42 @@ func TestRouter_createNewFilm(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().CreateFilm(film).Return(0, errors.New("something went wrong"))
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateFilm(userId, film).Return(0, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+		{
+			name:      "Wrong Field Type",
+			inputBody: `{"title": "title", "issue_date": "11-08-2023", "rating" : "five"}`,
+			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid type for field(s)","details":{"rating":"expected int"}}`,
+		},
+		{
+			name:      "Conflict",
+			inputBody: `{"title": "title", "description": "", "issue_date": "11-08-2023", "rating" : 5, "cast": []}`,
+			inputFilm: filmoteka.InputFilm{
+				Film: filmoteka.Film{
+					Title:       "title",
+					Description: "",
+					IssueDate:   (*filmoteka.Date)(&date),
+					Rating:      5,
+				},
+				Cast: nil,
+			},
+			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.InputFilm) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateFilm(userId, film).Return(0, filmoteka.ConflictError("record already exists",
+					map[string]string{"title": "already exists"}))
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: `{"code":"conflict","message":"record already exists","details":{"title":"already exists"}}`,
 		},
 	}
 
@@ -192,8 +285,8 @@ func TestRouter_updateFilm(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.UpdateFilmInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().UpdateFilm(film).Return(nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().UpdateFilm(userId, film).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `"successfully update"`,
@@ -214,8 +307,8 @@ func TestRouter_updateFilm(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.UpdateFilmInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().UpdateFilm(film).Return(nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().UpdateFilm(userId, film).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `"successfully update"`,
@@ -232,10 +325,10 @@ func TestRouter_updateFilm(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.UpdateFilmInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   400,
-			expectedResponseBody: `no id specified to update film`,
+			expectedResponseBody: `{"code":"validation_error","message":"no id specified to update film"}`,
 		},
 		{
 			name:        "Wrong Input",
@@ -245,10 +338,10 @@ func TestRouter_updateFilm(t *testing.T) {
 			inputFilm:   filmoteka.UpdateFilmInput{},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.UpdateFilmInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   400,
-			expectedResponseBody: `invalid state for required filed(s)`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required filed(s)"}`,
 		},
 		{
 			name:        "Locked",
@@ -258,10 +351,28 @@ func TestRouter_updateFilm(t *testing.T) {
 			inputFilm:   filmoteka.UpdateFilmInput{},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.UpdateFilmInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(false, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.RegularRole, nil)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
+		},
+		{
+			name:        "Not Found",
+			paramsName:  "id",
+			paramsValue: "1",
+			inputBody:   `{"title": "title", "issue_date": "11-08-2023"}`,
+			inputFilm: filmoteka.UpdateFilmInput{
+				Id:        &filmId,
+				Title:     &titleUpdate,
+				IssueDate: (*filmoteka.Date)(&date),
+			},
+			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.UpdateFilmInput) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().UpdateFilm(userId, film).Return(filmoteka.NotFoundError("film not found"))
 			},
-			expectedStatusCode:   423,
-			expectedResponseBody: `this function locked for current user`,
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"not_found","message":"film not found"}`,
 		},
 		{
 			name:        "Service Error",
@@ -275,11 +386,11 @@ func TestRouter_updateFilm(t *testing.T) {
 			},
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser, film filmoteka.UpdateFilmInput) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().UpdateFilm(film).Return(errors.New("something went wrong"))
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().UpdateFilm(userId, film).Return(errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -315,13 +426,13 @@ func TestRouter_updateFilm(t *testing.T) {
 
 func TestRouter_getSortedFilmsList(t *testing.T) {
 	// Init Test Table
-	type mockBehavior func(r *mock_service.MockFilm, sortBy string, page int)
+	type mockBehavior func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int)
 
 	var (
-		date  = time.Time{}.AddDate(2022, 7, 10)
-		limit = 10
-		film  = []filmoteka.InputFilm{{
+		date = time.Time{}.AddDate(2022, 7, 10)
+		film = []filmoteka.InputFilm{{
 			Film: filmoteka.Film{
+				Id:          7,
 				Title:       "test",
 				Description: "test",
 				IssueDate:   (*filmoteka.Date)(&date),
@@ -331,14 +442,22 @@ func TestRouter_getSortedFilmsList(t *testing.T) {
 				Name:    "name",
 				Surname: "surname",
 			}},
+			Genres: []string{"drama"},
 		}}
+		item     = `{"id":7,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0,"Cast":[{"name":"name","surname":"surname"}],"genres":["drama"]}`
+		genre    = "drama"
+		byRating = []filmoteka.SortKey{{Column: "avg_rating", Desc: true}}
 	)
 
 	tests := []struct {
 		name                 string
 		params               string
 		page                 int
-		sortBy               string
+		size                 int
+		after                int
+		sort                 []filmoteka.SortKey
+		genre                *string
+		maxPageSize          int
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -347,70 +466,197 @@ func TestRouter_getSortedFilmsList(t *testing.T) {
 			name:   "Ok default",
 			params: "page=1",
 			page:   1,
-			sortBy: "rating",
-			mockBehavior: func(r *mock_service.MockFilm, sortBy string, page int) {
-				r.EXPECT().GetSortedFilmList(sortBy, page, limit).Return(film, nil)
+			size:   10,
+			sort:   byRating,
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(film, 1, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `[{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"Cast":[{"name":"name","surname":"surname"}]}]`,
+			expectedResponseBody: `{"items":[` + item + `],"page":1,"page_size":10,"total":1}`,
 		},
 		{
 			name:   "Ok with sort",
 			params: "sort_by=issue_date&page=1",
 			page:   1,
-			sortBy: "issue_date",
-			mockBehavior: func(r *mock_service.MockFilm, sortBy string, page int) {
-				r.EXPECT().GetSortedFilmList(sortBy, page, limit).Return(film, nil)
+			size:   10,
+			sort:   []filmoteka.SortKey{{Column: "issue_date", Desc: true}},
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(film, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[` + item + `],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:   "Ok multi-key sort",
+			params: "sort=-rating,title&page=1",
+			page:   1,
+			size:   10,
+			sort:   []filmoteka.SortKey{{Column: "rating", Desc: true}, {Column: "title"}},
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(film, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[` + item + `],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:   "Ok with genre",
+			params: "genre=drama&page=1",
+			page:   1,
+			size:   10,
+			sort:   byRating,
+			genre:  &genre,
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(film, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[` + item + `],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:   "Ok no page",
+			params: "",
+			page:   1,
+			size:   10,
+			sort:   byRating,
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(film, 1, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `[{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"Cast":[{"name":"name","surname":"surname"}]}]`,
+			expectedResponseBody: `{"items":[` + item + `],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:   "Ok links",
+			params: "genre=drama&page=2&page_size=1",
+			page:   2,
+			size:   1,
+			sort:   byRating,
+			genre:  &genre,
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(film, 3, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[` + item + `],"page":2,"page_size":1,"total":3,` +
+				`"next":"/films/list?after=7\u0026genre=drama\u0026page_size=1",` +
+				`"prev":"/films/list?genre=drama\u0026page=1\u0026page_size=1"}`,
+		},
+		{
+			name:   "Ok after",
+			params: "page_size=1&after=5",
+			page:   1,
+			size:   1,
+			after:  5,
+			sort:   byRating,
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(film, 3, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[` + item + `],"page_size":1,"total":3,` +
+				`"next":"/films/list?after=7\u0026page_size=1"}`,
+		},
+		{
+			name:   "Ok after last page",
+			params: "page_size=2&after=5",
+			page:   1,
+			size:   2,
+			after:  5,
+			sort:   byRating,
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(film, 3, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[` + item + `],"page_size":2,"total":3}`,
+		},
+		{
+			name:                 "After with page",
+			params:               "page=3&page_size=1&after=7",
+			mockBehavior:         func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"page cannot be combined with after","details":{"page":"not allowed with after"}}`,
+		},
+		{
+			name:        "Ok max page size",
+			params:      "page=1&page_size=1000",
+			page:        1,
+			size:        50,
+			sort:        byRating,
+			maxPageSize: 50,
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(film, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[` + item + `],"page":1,"page_size":50,"total":1}`,
+		},
+		{
+			name:   "Over page",
+			params: "page=2",
+			page:   2,
+			size:   10,
+			sort:   byRating,
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(nil, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/films/list?page=1\u0026page_size=10"}`,
 		},
 		{
 			name:                 "Wrong Params",
-			params:               "paage=1",
-			page:                 1,
-			mockBehavior:         func(r *mock_service.MockFilm, sortBy string, page int) {},
+			params:               "page=one",
+			mockBehavior:         func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `no page specified for sorted list`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid page number","details":{"page":"expected int"}}`,
 		},
 		{
 			name:                 "Wrong Input page",
 			params:               "page=-1",
-			page:                 -1,
-			mockBehavior:         func(r *mock_service.MockFilm, sortBy string, page int) {},
+			mockBehavior:         func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"page out of bounds"}`,
+		},
+		{
+			name:                 "Wrong Page Size",
+			params:               "page=1&page_size=0",
+			mockBehavior:         func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `page out of bounds`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid page size","details":{"page_size":"expected positive int"}}`,
+		},
+		{
+			name:                 "Wrong After",
+			params:               "page=1&after=x",
+			mockBehavior:         func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid film id to continue the list after"}`,
 		},
 		{
 			name:                 "Wrong Input sort",
 			params:               "sort_by=smt&page=1",
-			page:                 1,
-			sortBy:               "smt",
-			mockBehavior:         func(r *mock_service.MockFilm, sortBy string, page int) {},
+			mockBehavior:         func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `invalid parameter to sort films list`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid parameter to sort films list"}`,
 		},
 		{
-			name:   "Over page",
-			params: "page=2",
-			page:   2,
-			sortBy: "rating",
-			mockBehavior: func(r *mock_service.MockFilm, sortBy string, page int) {
-				r.EXPECT().GetSortedFilmList(sortBy, page, limit).Return([]filmoteka.InputFilm{}, nil)
-			},
+			name:                 "Wrong Sort Column",
+			params:               "sort=-rating,votes%3BDROP%20TABLE%20films",
+			mockBehavior:         func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid sort","details":{"sort":"unknown column votes;DROP TABLE films"}}`,
+		},
+		{
+			name:                 "Repeated Sort Column",
+			params:               "sort=title,-title",
+			mockBehavior:         func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `page out of bounds`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid sort","details":{"sort":"repeated column title"}}`,
 		},
 		{
 			name:   "Service Error",
 			params: "page=1",
 			page:   1,
-			sortBy: "rating",
-			mockBehavior: func(r *mock_service.MockFilm, sortBy string, page int) {
-				r.EXPECT().GetSortedFilmList(sortBy, page, limit).Return(nil, errors.New("something went wrong"))
+			size:   10,
+			sort:   byRating,
+			mockBehavior: func(r *mock_service.MockFilm, sort []filmoteka.SortKey, genre *string, after, page, size int) {
+				r.EXPECT().GetSortedFilmList(sort, genre, after, page, size).Return(nil, 0, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -421,10 +667,10 @@ func TestRouter_getSortedFilmsList(t *testing.T) {
 			defer c.Finish()
 
 			repo := mock_service.NewMockFilm(c)
-			test.mockBehavior(repo, test.sortBy, test.page)
+			test.mockBehavior(repo, test.sort, test.genre, test.after, test.page, test.size)
 
 			services := &service.Service{Film: repo}
-			handler := Router{service: services}
+			handler := Router{service: services, maxPageSize: test.maxPageSize}
 			handler.AddEndPoint("GET", "/films/list", getSortedFilmList)
 
 			// Create Request
@@ -460,6 +706,21 @@ func TestRouter_getCurrentFilm(t *testing.T) {
 				Surname: "surname",
 			}},
 		}
+		billed = filmoteka.InputFilm{
+			Film: film.Film,
+			Cast: []filmoteka.InputActor{
+				{Name: "lead", Surname: "surname", Character: "hero", Billing: 1},
+				{Name: "name", Surname: "surname", Character: "villain", Billing: 2},
+			},
+		}
+		credited = filmoteka.InputFilm{
+			Film: film.Film,
+			Cast: film.Cast,
+			Credits: filmoteka.Credits{"director": {{
+				Name:    "director",
+				Surname: "surname",
+			}}},
+		}
 	)
 
 	tests := []struct {
@@ -476,21 +737,39 @@ func TestRouter_getCurrentFilm(t *testing.T) {
 				r.EXPECT().GetCurFilm(filmId).Return(film, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"Cast":[{"name":"name","surname":"surname"}]}`,
+			expectedResponseBody: `{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0,"Cast":[{"name":"name","surname":"surname"}]}`,
+		},
+		{
+			name:   "Ok with characters",
+			params: "id=1",
+			mockBehavior: func(r *mock_service.MockFilm) {
+				r.EXPECT().GetCurFilm(filmId).Return(billed, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0,"Cast":[{"name":"lead","surname":"surname","character":"hero","billing":1},{"name":"name","surname":"surname","character":"villain","billing":2}]}`,
+		},
+		{
+			name:   "Ok with credits",
+			params: "id=1",
+			mockBehavior: func(r *mock_service.MockFilm) {
+				r.EXPECT().GetCurFilm(filmId).Return(credited, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0,"Cast":[{"name":"name","surname":"surname"}],"credits":{"director":[{"name":"director","surname":"surname"}]}}`,
 		},
 		{
 			name:                 "Wrong Params",
 			params:               "idd=1",
 			mockBehavior:         func(r *mock_service.MockFilm) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `id for get film not specified`,
+			expectedResponseBody: `{"code":"validation_error","message":"id for get film not specified"}`,
 		},
 		{
 			name:                 "Wrong Input",
 			params:               "id=-1",
 			mockBehavior:         func(r *mock_service.MockFilm) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `id out of bounds`,
+			expectedResponseBody: `{"code":"validation_error","message":"id out of bounds"}`,
 		},
 		{
 			name:   "Service Error",
@@ -499,7 +778,16 @@ func TestRouter_getCurrentFilm(t *testing.T) {
 				r.EXPECT().GetCurFilm(filmId).Return(filmoteka.InputFilm{}, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+		{
+			name:   "Not Found",
+			params: "id=1",
+			mockBehavior: func(r *mock_service.MockFilm) {
+				r.EXPECT().GetCurFilm(filmId).Return(filmoteka.InputFilm{}, filmoteka.NotFoundError("film not found"))
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"not_found","message":"film not found"}`,
 		},
 	}
 
@@ -531,6 +819,84 @@ func TestRouter_getCurrentFilm(t *testing.T) {
 	}
 }
 
+func TestRouter_getFilmCast(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm)
+
+	var (
+		filmId = 42
+		cast   = filmoteka.Cast{{Name: "lead", Surname: "surname", Character: "hero", Billing: 1}}
+	)
+
+	tests := []struct {
+		name                 string
+		target               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			target: "/films/42/cast",
+			mockBehavior: func(r *mock_service.MockFilm) {
+				r.EXPECT().GetFilmCast(filmId).Return(cast, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"name":"lead","surname":"surname","character":"hero","billing":1}]`,
+		},
+		{
+			name:                 "Wrong Params",
+			target:               "/films/film/cast",
+			mockBehavior:         func(r *mock_service.MockFilm) {},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"not_found","message":"this uri not found /films/film/cast"}`,
+		},
+		{
+			name:                 "Wrong Input",
+			target:               "/films/0/cast",
+			mockBehavior:         func(r *mock_service.MockFilm) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"id out of bounds"}`,
+		},
+		{
+			name:   "Service Error",
+			target: "/films/42/cast",
+			mockBehavior: func(r *mock_service.MockFilm) {
+				r.EXPECT().GetFilmCast(filmId).Return(nil, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{Film: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/films/{id:int}/cast", getFilmCast)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", test.target,
+				bytes.NewBufferString(""))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
 func TestRouter_getSearchFilmList(t *testing.T) {
 	// Init Test Table
 	type mockBehavior func(r *mock_service.MockFilm, fragment filmoteka.FilmSearchFragment, page int)
@@ -538,21 +904,31 @@ func TestRouter_getSearchFilmList(t *testing.T) {
 	var (
 		date  = time.Time{}.AddDate(2022, 7, 10)
 		limit = 10
-		film  = []filmoteka.InputFilm{{
-			Film: filmoteka.Film{
-				Title:       "test",
-				Description: "test",
-				IssueDate:   (*filmoteka.Date)(&date),
-				Rating:      5,
+		film  = []filmoteka.FilmSearchResult{{
+			InputFilm: filmoteka.InputFilm{
+				Film: filmoteka.Film{
+					Title:       "test",
+					Description: "test",
+					IssueDate:   (*filmoteka.Date)(&date),
+					Rating:      5,
+				},
+				Cast: []filmoteka.InputActor{{
+					Name:    "name",
+					Surname: "surname",
+				}},
 			},
-			Cast: []filmoteka.InputActor{{
-				Name:    "name",
-				Surname: "surname",
-			}},
 		}}
 		title   = "te"
 		name    = "na"
 		surname = "s"
+		query   = "tests"
+		snippet = "<b>test</b>"
+		ranked  = []filmoteka.FilmSearchResult{{
+			InputFilm:    film[0].InputFilm,
+			Rank:         0.6,
+			TitleSnippet: &snippet,
+			Snippet:      &snippet,
+		}}
 	)
 
 	tests := []struct {
@@ -576,10 +952,10 @@ func TestRouter_getSearchFilmList(t *testing.T) {
 				Surname: nil,
 			},
 			mockBehavior: func(r *mock_service.MockFilm, fragment filmoteka.FilmSearchFragment, page int) {
-				r.EXPECT().GetSearchFilmList(page, limit, fragment).Return(film, nil)
+				r.EXPECT().GetSearchFilmList(page, limit, fragment).Return(film, 1, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `[{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"Cast":[{"name":"name","surname":"surname"}]}]`,
+			expectedResponseBody: `{"items":[{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0,"Cast":[{"name":"name","surname":"surname"}]}],"page":1,"page_size":10,"total":1}`,
 		},
 		{
 			name:      "Ok search by actor",
@@ -591,18 +967,40 @@ func TestRouter_getSearchFilmList(t *testing.T) {
 				Surname: &surname,
 			},
 			mockBehavior: func(r *mock_service.MockFilm, fragment filmoteka.FilmSearchFragment, page int) {
-				r.EXPECT().GetSearchFilmList(page, limit, fragment).Return(film, nil)
+				r.EXPECT().GetSearchFilmList(page, limit, fragment).Return(film, 1, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `[{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"Cast":[{"name":"name","surname":"surname"}]}]`,
+			expectedResponseBody: `{"items":[{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0,"Cast":[{"name":"name","surname":"surname"}]}],"page":1,"page_size":10,"total":1}`,
 		},
 		{
-			name:                 "Wrong Params",
-			params:               "paage=1",
+			name:      "Ok full-text search",
+			params:    "page=1",
+			page:      1,
+			inputBody: `{"query": "tests"}`,
+			fragment: filmoteka.FilmSearchFragment{
+				Query: &query,
+			},
+			mockBehavior: func(r *mock_service.MockFilm, fragment filmoteka.FilmSearchFragment, page int) {
+				r.EXPECT().GetSearchFilmList(page, limit, fragment).Return(ranked, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0,"Cast":[{"name":"name","surname":"surname"}],"rank":0.6,"title_snippet":"\u003cb\u003etest\u003c/b\u003e","snippet":"\u003cb\u003etest\u003c/b\u003e"}],"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:                 "Wrong Full-text Input",
+			params:               "page=1",
 			page:                 1,
+			inputBody:            `{"query": "tests", "title": "te"}`,
+			mockBehavior:         func(r *mock_service.MockFilm, fragment filmoteka.FilmSearchFragment, page int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"full-text query can not be combined with title, name or surname"}`,
+		},
+		{
+			name:                 "Wrong Params",
+			params:               "page=0",
 			mockBehavior:         func(r *mock_service.MockFilm, fragment filmoteka.FilmSearchFragment, page int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `no page specified for search list`,
+			expectedResponseBody: `{"code":"validation_error","message":"page out of bounds"}`,
 		},
 		{
 			name:                 "Wrong Input",
@@ -612,22 +1010,22 @@ func TestRouter_getSearchFilmList(t *testing.T) {
 			fragment:             filmoteka.FilmSearchFragment{},
 			mockBehavior:         func(r *mock_service.MockFilm, fragment filmoteka.FilmSearchFragment, page int) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `parameters for search not specified`,
+			expectedResponseBody: `{"code":"validation_error","message":"parameters for search not specified"}`,
 		},
 		{
-			name:      "Bad request",
-			params:    "page=1",
-			page:      1,
+			name:      "Empty page",
+			params:    "page=2",
+			page:      2,
 			inputBody: `{"name":"na", "surname": "s"}`,
 			fragment: filmoteka.FilmSearchFragment{
 				Name:    &name,
 				Surname: &surname,
 			},
 			mockBehavior: func(r *mock_service.MockFilm, fragment filmoteka.FilmSearchFragment, page int) {
-				r.EXPECT().GetSearchFilmList(page, limit, fragment).Return([]filmoteka.InputFilm{}, nil)
+				r.EXPECT().GetSearchFilmList(page, limit, fragment).Return([]filmoteka.FilmSearchResult{}, 1, nil)
 			},
-			expectedStatusCode:   400,
-			expectedResponseBody: `nothing find`,
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/films/search?page=1\u0026page_size=10"}`,
 		},
 		{
 			name:      "Service Error",
@@ -639,10 +1037,10 @@ func TestRouter_getSearchFilmList(t *testing.T) {
 				Surname: &surname,
 			},
 			mockBehavior: func(r *mock_service.MockFilm, fragment filmoteka.FilmSearchFragment, page int) {
-				r.EXPECT().GetSearchFilmList(page, limit, fragment).Return(nil, errors.New("something went wrong"))
+				r.EXPECT().GetSearchFilmList(page, limit, fragment).Return(nil, 0, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -674,6 +1072,155 @@ func TestRouter_getSearchFilmList(t *testing.T) {
 	}
 }
 
+func TestRouter_getFilteredFilmList(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, filter filmoteka.FilmFilter, page int)
+
+	var (
+		date  = time.Time{}.AddDate(2022, 7, 10)
+		limit = 10
+		film  = []filmoteka.InputFilm{{
+			Film: filmoteka.Film{
+				Title:       "test",
+				Description: "test",
+				IssueDate:   (*filmoteka.Date)(&date),
+				Rating:      5,
+			},
+			Cast: []filmoteka.InputActor{{
+				Name:    "name",
+				Surname: "surname",
+			}},
+		}}
+		yearFrom  = 2000
+		ratingMin = 4
+		facets    = filmoteka.Facets{
+			Years:   []filmoteka.FacetBucket{{From: 2020, To: 2029, Count: 1}},
+			Ratings: []filmoteka.FacetBucket{{From: 5, To: 5, Count: 1}},
+		}
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		page                 int
+		inputBody            string
+		filter               filmoteka.FilmFilter
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			params:    "page=1",
+			page:      1,
+			inputBody: `{"year_from": 2000, "rating_min": 4, "actors": [1, 2], "actor_match": "any", "exclude_terms": ["horror"]}`,
+			filter: filmoteka.FilmFilter{
+				YearFrom:     &yearFrom,
+				RatingMin:    &ratingMin,
+				GenreMatch:   filmoteka.MatchAll,
+				Actors:       []int{1, 2},
+				ActorMatch:   filmoteka.MatchAny,
+				ExcludeTerms: []string{"horror"},
+				YearBucket:   10,
+			},
+			mockBehavior: func(r *mock_service.MockFilm, filter filmoteka.FilmFilter, page int) {
+				r.EXPECT().GetFilteredFilmList(page, limit, filter).Return(film, 1, facets, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[{"id":0,"title":"test","description":"test","issue_date":"11-08-2023","rating":5,"avg_rating":0,"votes":0,"Cast":[{"name":"name","surname":"surname"}]}],"page":1,"page_size":10,"total":1,"facets":{"years":[{"from":2020,"to":2029,"count":1}],"ratings":[{"from":5,"to":5,"count":1}]}}`,
+		},
+		{
+			name:      "Ok empty filter",
+			params:    "page=2",
+			page:      2,
+			inputBody: `{}`,
+			filter: filmoteka.FilmFilter{
+				GenreMatch: filmoteka.MatchAll,
+				ActorMatch: filmoteka.MatchAll,
+				YearBucket: 10,
+			},
+			mockBehavior: func(r *mock_service.MockFilm, filter filmoteka.FilmFilter, page int) {
+				r.EXPECT().GetFilteredFilmList(page, limit, filter).
+					Return([]filmoteka.InputFilm{}, 1, filmoteka.Facets{Years: []filmoteka.FacetBucket{},
+						Ratings: []filmoteka.FacetBucket{}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/films/filter?page=1\u0026page_size=10","facets":{"years":[],"ratings":[]}}`,
+		},
+		{
+			name:                 "Wrong Year Range",
+			params:               "page=1",
+			page:                 1,
+			inputBody:            `{"year_from": 2010, "year_to": 2000}`,
+			mockBehavior:         func(r *mock_service.MockFilm, filter filmoteka.FilmFilter, page int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"year_from is after year_to"}`,
+		},
+		{
+			name:                 "Wrong Rating",
+			params:               "page=1",
+			page:                 1,
+			inputBody:            `{"rating_max": 11}`,
+			mockBehavior:         func(r *mock_service.MockFilm, filter filmoteka.FilmFilter, page int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"rating range must be within 0 and 10"}`,
+		},
+		{
+			name:                 "Wrong Match",
+			params:               "page=1",
+			page:                 1,
+			inputBody:            `{"genres": ["drama"], "genre_match": "some"}`,
+			mockBehavior:         func(r *mock_service.MockFilm, filter filmoteka.FilmFilter, page int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"match must be all or any"}`,
+		},
+		{
+			name:      "Service Error",
+			params:    "page=1",
+			page:      1,
+			inputBody: `{}`,
+			filter: filmoteka.FilmFilter{
+				GenreMatch: filmoteka.MatchAll,
+				ActorMatch: filmoteka.MatchAll,
+				YearBucket: 10,
+			},
+			mockBehavior: func(r *mock_service.MockFilm, filter filmoteka.FilmFilter, page int) {
+				r.EXPECT().GetFilteredFilmList(page, limit, filter).
+					Return(nil, 0, filmoteka.Facets{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			test.mockBehavior(repo, test.filter, test.page)
+
+			services := &service.Service{Film: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/films/filter", getFilteredFilmList)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/films/filter?"+test.params,
+				bytes.NewBufferString(test.inputBody))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
 func TestRouter_deleteFilm(t *testing.T) {
 	// Init Test Table
 	type mockBehavior func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser)
@@ -683,7 +1230,7 @@ func TestRouter_deleteFilm(t *testing.T) {
 		headerValue = "Bearer test"
 		token       = "test"
 		userId      = 1
-		actorId     = 1
+		filmId      = 3
 	)
 
 	tests := []struct {
@@ -695,11 +1242,11 @@ func TestRouter_deleteFilm(t *testing.T) {
 	}{
 		{
 			name:   "Ok",
-			params: "id=1",
+			params: "id=3",
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().DeleteFilmById(actorId).Return(nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().DeleteFilmById(userId, filmId).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `"successfully delete"`,
@@ -709,31 +1256,31 @@ func TestRouter_deleteFilm(t *testing.T) {
 			params: "id=fksfm",
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   400,
-			expectedResponseBody: `id doesnt specified to delete film`,
+			expectedResponseBody: `{"code":"validation_error","message":"id doesnt specified to delete film"}`,
 		},
 		{
 			name:   "Locked",
-			params: "id=1",
+			params: "id=3",
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(false, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.RegularRole, nil)
 			},
-			expectedStatusCode:   423,
-			expectedResponseBody: `this function locked for current user`,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
 		},
 		{
 			name:   "Service Error",
-			params: "id=1",
+			params: "id=3",
 			mockBehavior: func(r1 *mock_service.MockFilm, r2 *mock_service.MockUser) {
 				r2.EXPECT().ParseToken(token).Return(userId, nil)
-				r2.EXPECT().ValidateUser(userId).Return(true, nil)
-				r1.EXPECT().DeleteFilmById(actorId).Return(errors.New("something went wrong"))
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().DeleteFilmById(userId, filmId).Return(errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+func createNewGenre(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	var genre filmoteka.Genre
+	if err := parseBody(request.Body, &genre); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	genreId, err := r.service.Genre.CreateGenre(genre)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, fmt.Sprintf("successfully create genre with id %d", genreId)); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("new genre with id %d was created by user with id %d", genreId, id)
+}
+
+func updateGenre(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	genreId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "no id specified to update genre")
+		return
+	}
+
+	var genre filmoteka.Genre
+	if err = parseBody(request.Body, &genre); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	genre.Id = genreId
+
+	if err = r.service.Genre.UpdateGenre(genre); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully update"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("genre with id %d was updated by user with id %d", genreId, id)
+}
+
+func getGenresList(r *Router, writer http.ResponseWriter, request *http.Request) {
+	genres, err := r.service.Genre.GetGenresList()
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, genres); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Info("list of genres was sent to user")
+}
+
+func getGenreById(r *Router, writer http.ResponseWriter, request *http.Request) {
+	genreId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id for get genre not specified")
+		return
+	}
+	if genreId < 1 {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id out of bounds")
+		return
+	}
+
+	genre, err := r.service.Genre.GetGenreById(genreId)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, genre); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("genre with id %d was sent to user", genreId)
+}
+
+func deleteGenre(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	genreId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id doesnt specified to delete genre")
+		return
+	}
+
+	if err = r.service.Genre.DeleteGenreById(genreId); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully delete"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("genre with id %d was deleted by user with id %d", genreId, id)
+}
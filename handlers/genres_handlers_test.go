@@ -0,0 +1,413 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/service"
+	"github.com/jorgini/filmoteka/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouter_createNewGenre(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser, genre filmoteka.Genre)
+
+	var (
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+	)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		inputGenre           filmoteka.Genre
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "Ok",
+			inputBody:  `{"name": "drama"}`,
+			inputGenre: filmoteka.Genre{Name: "drama"},
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser, genre filmoteka.Genre) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateGenre(genre).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully create genre with id 1"`,
+		},
+		{
+			name:       "Wrong Input",
+			inputBody:  `{"name": ""}`,
+			inputGenre: filmoteka.Genre{},
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser, genre filmoteka.Genre) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required field(s)"}`,
+		},
+		{
+			name:       "Locked",
+			inputBody:  `{"name": "drama"}`,
+			inputGenre: filmoteka.Genre{Name: "drama"},
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser, genre filmoteka.Genre) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.RegularRole, nil)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
+		},
+		{
+			name:       "Service Error",
+			inputBody:  `{"name": "drama"}`,
+			inputGenre: filmoteka.Genre{Name: "drama"},
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser, genre filmoteka.Genre) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().CreateGenre(genre).Return(0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockGenre(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2, test.inputGenre)
+
+			services := &service.Service{Genre: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("POST", "/genres", createNewGenre)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/genres",
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_updateGenre(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser, genre filmoteka.Genre)
+
+	var (
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		inputBody            string
+		inputGenre           filmoteka.Genre
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "Ok",
+			params:     "id=1",
+			inputBody:  `{"name": "comedy"}`,
+			inputGenre: filmoteka.Genre{Id: 1, Name: "comedy"},
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser, genre filmoteka.Genre) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().UpdateGenre(genre).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully update"`,
+		},
+		{
+			name:       "Wrong Params",
+			params:     "id=fafmek",
+			inputBody:  `{"name": "comedy"}`,
+			inputGenre: filmoteka.Genre{},
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser, genre filmoteka.Genre) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"no id specified to update genre"}`,
+		},
+		{
+			name:       "Service Error",
+			params:     "id=1",
+			inputBody:  `{"name": "comedy"}`,
+			inputGenre: filmoteka.Genre{Id: 1, Name: "comedy"},
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser, genre filmoteka.Genre) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().UpdateGenre(genre).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockGenre(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2, test.inputGenre)
+
+			services := &service.Service{Genre: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("PUT", "/genres", updateGenre)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/genres?"+test.params,
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_getGenresList(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockGenre)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(r *mock_service.MockGenre) {
+				r.EXPECT().GetGenresList().Return([]filmoteka.Genre{{Id: 1, Name: "drama"}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"id":1,"name":"drama"}]`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(r *mock_service.MockGenre) {
+				r.EXPECT().GetGenresList().Return(nil, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockGenre(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{Genre: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/genres/list", getGenresList)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/genres/list", bytes.NewBufferString(""))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_getGenreById(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockGenre)
+
+	var genreId = 1
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "id=1",
+			mockBehavior: func(r *mock_service.MockGenre) {
+				r.EXPECT().GetGenreById(genreId).Return(filmoteka.Genre{Id: 1, Name: "drama"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1,"name":"drama"}`,
+		},
+		{
+			name:                 "Wrong Params",
+			params:               "idd=1",
+			mockBehavior:         func(r *mock_service.MockGenre) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"id for get genre not specified"}`,
+		},
+		{
+			name:                 "Wrong Input",
+			params:               "id=-1",
+			mockBehavior:         func(r *mock_service.MockGenre) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"id out of bounds"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "id=1",
+			mockBehavior: func(r *mock_service.MockGenre) {
+				r.EXPECT().GetGenreById(genreId).Return(filmoteka.Genre{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockGenre(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{Genre: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/genres", getGenreById)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/genres?"+test.params, bytes.NewBufferString(""))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_deleteGenre(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser)
+
+	var (
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+		genreId     = 1
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().DeleteGenreById(genreId).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully delete"`,
+		},
+		{
+			name:   "Wrong Params",
+			params: "id=fksfm",
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"id doesnt specified to delete genre"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockGenre, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().DeleteGenreById(genreId).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockGenre(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2)
+
+			services := &service.Service{Genre: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("DELETE", "/genres", deleteGenre)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/genres?"+test.params,
+				bytes.NewBufferString(""))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/jorgini/filmoteka"
 	"github.com/jorgini/filmoteka/service"
 	"github.com/sirupsen/logrus"
 	"io"
@@ -12,77 +14,260 @@ import (
 
 type Router struct {
 	http.Handler
-	service   *service.Service
-	endpoints map[string]map[string]func(r *Router, writer http.ResponseWriter, request *http.Request)
-	abort     bool
+	service     *service.Service
+	routes      map[string]*route
+	patterns    []*route
+	middlewares []Middleware
+	maxPageSize int
+}
+
+// Use adds layers wrapping every request, including the ones that match no route.
+func (r *Router) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
 }
 
 func (r *Router) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	route := strings.Split(request.RequestURI, "?")[0]
+	chain(http.HandlerFunc(r.dispatch), r.middlewares...).ServeHTTP(writer, request)
+}
 
-	if _, ok := r.endpoints[request.Method]; !ok {
-		r.sendErrorResponse(writer, http.StatusMethodNotAllowed, "this method not provided")
-		r.abort = false
+func (r *Router) dispatch(writer http.ResponseWriter, request *http.Request) {
+	path := strings.Split(request.RequestURI, "?")[0]
+
+	rt, params := r.findRoute(path)
+	if rt == nil {
+		r.sendErrorResponse(writer, http.StatusNotFound, fmt.Sprintf("this uri not found %s", path))
 		return
 	}
 
-	if handle, ok := r.endpoints[request.Method][route]; !ok {
-		r.sendErrorResponse(writer, http.StatusNotFound, fmt.Sprintf("this uri not found %s", route))
-	} else {
-		if request.Method == "GET" || (request.Method == "POST" && route == "/users") {
-		} else if request.Method == "DELETE" && route == "/users" {
-			userIdentity(r, writer, request)
+	method := request.Method
+	handler, ok := rt.methods[method]
+	if !ok && method == "HEAD" {
+		method = "GET"
+		handler, ok = rt.methods[method]
+		writer = headResponseWriter{writer}
+	}
+
+	if !ok {
+		writer.Header().Set("Allow", rt.allow())
+		if method == "OPTIONS" {
+			writer.WriteHeader(http.StatusNoContent)
 		} else {
-			validation(r, writer, request)
-		}
-		if !r.abort {
-			handle(r, writer, request)
+			r.sendErrorResponse(writer, http.StatusMethodNotAllowed, "this method not provided")
 		}
+		return
 	}
-	r.abort = false
+
+	handler.ServeHTTP(writer, withParams(request, params))
 }
 
-func NewRouter(service *service.Service) *Router {
-	return &Router{
-		service: service,
-		endpoints: map[string]map[string]func(r *Router, writer http.ResponseWriter, request *http.Request){
-			"POST": {"/users": createNewUser, "/actors": createNewActor, "/films": createNewFilm},
-			"GET": {"/actors/list": getActorsList, "/actors": getActorById, "/actors/search": searchActor,
-				"/films/list": getSortedFilmList, "/films": getCurrentFilm, "/films/search": getSearchFilmList,
-				"/users": authUser},
-			"PUT":    {"/actors": updateActor, "/films": updateFilm, "/users": updateUser},
-			"DELETE": {"/actors": deleteActor, "/films": deleteFilm, "/users": deleteUser},
-		},
-	}
+func NewRouter(service *service.Service, maxPageSize int) *Router {
+	router := &Router{service: service, maxPageSize: maxPageSize}
+	router.Use(requestId, recovery, logging)
+
+	router.Group("/users").
+		AddEndPoint("POST", "", createNewUser).
+		AddEndPoint("GET", "", authUser).
+		AddEndPoint("PUT", "", updateUser).
+		AddEndPoint("DELETE", "", deleteUser).
+		AddEndPoint("POST", "/refresh", refreshToken).
+		AddEndPoint("POST", "/logout", logout).
+		AddEndPoint("POST", "/logout/all", logoutEverywhere)
+
+	router.Group("/actors").
+		AddEndPoint("POST", "", createNewActor).
+		AddEndPoint("GET", "", getActorById).
+		AddEndPoint("PUT", "", updateActor).
+		AddEndPoint("DELETE", "", deleteActor).
+		AddEndPoint("GET", "/list", getActorsList).
+		AddEndPoint("GET", "/search", searchActor).
+		AddEndPoint("GET", "/{id:int}", getActorById).
+		AddEndPoint("DELETE", "/{id:int}", deleteActor)
+
+	router.Group("/films").
+		AddEndPoint("POST", "", createNewFilm).
+		AddEndPoint("GET", "", getCurrentFilm).
+		AddEndPoint("PUT", "", updateFilm).
+		AddEndPoint("DELETE", "", deleteFilm).
+		AddEndPoint("GET", "/list", getSortedFilmList).
+		AddEndPoint("GET", "/search", getSearchFilmList).
+		AddEndPoint("GET", "/filter", getFilteredFilmList).
+		AddEndPoint("GET", "/{id:int}", getCurrentFilm).
+		AddEndPoint("DELETE", "/{id:int}", deleteFilm).
+		AddEndPoint("GET", "/{id:int}/cast", getFilmCast).
+		AddEndPoint("GET", "/{film_id:int}/reviews", getFilmReviews)
+
+	router.Group("/genres").
+		AddEndPoint("POST", "", createNewGenre).
+		AddEndPoint("GET", "", getGenreById).
+		AddEndPoint("PUT", "", updateGenre).
+		AddEndPoint("DELETE", "", deleteGenre).
+		AddEndPoint("GET", "/list", getGenresList).
+		AddEndPoint("GET", "/{id:int}", getGenreById).
+		AddEndPoint("DELETE", "/{id:int}", deleteGenre)
+
+	router.Group("/reviews").
+		AddEndPoint("POST", "", createNewReview).
+		AddEndPoint("PUT", "", updateReview).
+		AddEndPoint("DELETE", "", deleteReview).
+		AddEndPoint("GET", "/list", getFilmReviews).
+		AddEndPoint("DELETE", "/moderate", moderateReview).
+		AddEndPoint("DELETE", "/{id:int}", deleteReview)
+
+	router.Group("/watchlist").
+		AddEndPoint("POST", "", addToWatchlist).
+		AddEndPoint("GET", "", getWatchlist).
+		AddEndPoint("DELETE", "", removeFromWatchlist).
+		AddEndPoint("DELETE", "/{film_id:int}", removeFromWatchlist)
+
+	router.Group("/collections").
+		AddEndPoint("POST", "", createNewCollection).
+		AddEndPoint("GET", "", getCollection).
+		AddEndPoint("PUT", "", updateCollection).
+		AddEndPoint("DELETE", "", deleteCollection).
+		AddEndPoint("GET", "/list", getPublicCollections).
+		AddEndPoint("GET", "/public", getCollection).
+		AddEndPoint("GET", "/mine", getUserCollections)
+
+	router.AddEndPoint("GET", "/audit", getAuditLog)
+
+	router.Group("/revisions").
+		AddEndPoint("GET", "/{entity}/{id:int}", getRevisions).
+		AddEndPoint("GET", "/{entity}/{id:int}/diff", diffRevisions).
+		AddEndPoint("POST", "/{entity}/{id:int}/{version:int}/rollback", rollbackRevision)
+
+	router.Group("/trash").
+		AddEndPoint("GET", "", getDeletedList).
+		AddEndPoint("POST", "/{entity}/{id:int}/restore", restoreDeleted)
+
+	router.Group("/search").
+		AddEndPoint("GET", "/suggest", getSuggestions)
+
+	router.AddEndPoint("GET", "/.well-known/jwks.json", getJWKS)
+
+	return router
 }
 
-func (r *Router) AddEndPoint(method, path string, handle func(r *Router, writer http.ResponseWriter, request *http.Request)) {
-	if r.endpoints == nil {
-		r.endpoints = make(map[string]map[string]func(r *Router, writer http.ResponseWriter, request *http.Request))
+// AddEndPoint registers handle for the method and path. The path may contain parameters
+// like /films/{id:int}/cast, the int ones match only numeric segments. The chain of the
+// route starts with the auth layers from the permissions table followed by middlewares.
+func (r *Router) AddEndPoint(method, path string, handle func(r *Router, writer http.ResponseWriter,
+	request *http.Request), middlewares ...Middleware) {
+	if r.routes == nil {
+		r.routes = make(map[string]*route)
 	}
 
-	if r.endpoints[method] == nil {
-		r.endpoints[method] = make(map[string]func(r *Router, writer http.ResponseWriter, request *http.Request))
+	rt, ok := r.routes[path]
+	if !ok {
+		rt = &route{pattern: path, segments: parsePattern(path), methods: make(map[string]http.Handler)}
+		for _, seg := range rt.segments {
+			rt.params = rt.params || seg.param
+		}
+
+		r.routes[path] = rt
+		if rt.params {
+			r.patterns = append(r.patterns, rt)
+		}
 	}
 
-	r.endpoints[method][path] = handle
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		handle(r, writer, request)
+	})
+	layers := append(r.accessChain(getAccess(method, path)), middlewares...)
+	rt.methods[method] = chain(handler, layers...)
+}
+
+type errorResponse struct {
+	Code      filmoteka.ErrorCode `json:"code"`
+	Message   string              `json:"message"`
+	Details   map[string]string   `json:"details,omitempty"`
+	RequestId string              `json:"request_id,omitempty"`
 }
 
+var (
+	errorStatuses = map[filmoteka.ErrorCode]int{
+		filmoteka.CodeValidation:       http.StatusBadRequest,
+		filmoteka.CodeUnauthorized:     http.StatusUnauthorized,
+		filmoteka.CodeForbidden:        http.StatusForbidden,
+		filmoteka.CodeNotFound:         http.StatusNotFound,
+		filmoteka.CodeMethodNotAllowed: http.StatusMethodNotAllowed,
+		filmoteka.CodeConflict:         http.StatusConflict,
+		filmoteka.CodeInternal:         http.StatusInternalServerError,
+	}
+	errorCodes = map[int]filmoteka.ErrorCode{
+		http.StatusBadRequest:          filmoteka.CodeValidation,
+		http.StatusUnauthorized:        filmoteka.CodeUnauthorized,
+		http.StatusForbidden:           filmoteka.CodeForbidden,
+		http.StatusNotFound:            filmoteka.CodeNotFound,
+		http.StatusMethodNotAllowed:    filmoteka.CodeMethodNotAllowed,
+		http.StatusConflict:            filmoteka.CodeConflict,
+		http.StatusInternalServerError: filmoteka.CodeInternal,
+	}
+)
+
+// sendErrorResponse reports a problem found by the handler itself, like a missing parameter.
 func (r *Router) sendErrorResponse(writer http.ResponseWriter, status int, message string) {
-	logrus.Error(message)
-	r.abort = true
+	code, ok := errorCodes[status]
+	if !ok {
+		code = filmoteka.CodeInternal
+	}
+	logrus.WithField("request_id", writer.Header().Get(requestIdHeader)).Error(message)
+	writeError(writer, status, &filmoteka.Error{Code: code, Message: message})
+}
+
+// sendError reports an error returned by the service. Untyped errors are internal ones,
+// their text is logged but never sent to the client.
+func (r *Router) sendError(writer http.ResponseWriter, err error) {
+	logrus.WithField("request_id", writer.Header().Get(requestIdHeader)).Error(err)
+
+	var e *filmoteka.Error
+	if !errors.As(err, &e) {
+		e = &filmoteka.Error{Code: filmoteka.CodeInternal, Message: "internal server error"}
+	}
+
+	status, ok := errorStatuses[e.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	writeError(writer, status, e)
+}
+
+func writeError(writer http.ResponseWriter, status int, e *filmoteka.Error) {
+	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(status)
-	if _, err := writer.Write([]byte(message)); err != nil {
-		writer.WriteHeader(http.StatusBadGateway)
+
+	err := writeBody(writer, errorResponse{
+		Code:      e.Code,
+		Message:   e.Message,
+		Details:   e.Details,
+		RequestId: writer.Header().Get(requestIdHeader),
+	})
+	if err != nil {
+		logrus.Errorf("error response was not written: %s", err)
 	}
 }
 
+// parseBody decodes the body into value, every failure is reported as a validation error
+// with the offending field in details when it is known.
 func parseBody(body io.ReadCloser, value interface{}) error {
 	dec := json.NewDecoder(body)
 	dec.DisallowUnknownFields()
 
 	if err := dec.Decode(value); err != nil {
-		return err
+		var e *filmoteka.Error
+		if errors.As(err, &e) {
+			return err
+		}
+
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return &filmoteka.Error{Code: filmoteka.CodeValidation, Message: "invalid type for field(s)",
+				Details: map[string]string{typeErr.Field: "expected " + typeErr.Type.String()}, Err: err}
+		}
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return &filmoteka.Error{Code: filmoteka.CodeValidation, Message: "unknown field(s)",
+				Details: map[string]string{strings.Trim(field, `"`): "unknown field"}, Err: err}
+		}
+		return &filmoteka.Error{Code: filmoteka.CodeValidation, Message: err.Error(), Err: err}
 	}
 	return nil
 }
@@ -2,77 +2,210 @@ package handlers
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"github.com/jorgini/filmoteka"
+	"github.com/sirupsen/logrus"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const (
 	authorizationHeader = "Authorization"
 	userCtx             = "userId"
+	tokenCtx            = "token"
+	roleCtx             = "role"
+	requestIdHeader     = "X-Request-Id"
 )
 
-func validation(r *Router, writer http.ResponseWriter, request *http.Request) {
-	header := request.Header.Get(authorizationHeader)
-	if header == "" {
-		r.sendErrorResponse(writer, http.StatusUnauthorized, "empty header")
-		return
+// access describes who may call an endpoint: everyone, any signed in user or only the listed roles.
+type access struct {
+	public  bool
+	anyUser bool
+	roles   map[string]struct{}
+}
+
+func allow(roles ...string) access {
+	a := access{roles: make(map[string]struct{}, len(roles))}
+	for _, role := range roles {
+		a.roles[role] = struct{}{}
 	}
+	return a
+}
 
-	headerParts := strings.Split(header, " ")
-	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-		r.sendErrorResponse(writer, http.StatusUnauthorized, "invalid auth header")
-		return
+var (
+	public     = access{public: true}
+	anyUser    = access{anyUser: true}
+	editors    = allow(filmoteka.EditorRole, filmoteka.AdminRole)
+	moderators = allow(filmoteka.ModeratorRole, filmoteka.AdminRole)
+	admins     = allow(filmoteka.AdminRole)
+
+	// permissions gives every route the auth layers of its chain. Endpoints missing from
+	// the table are public for GET and admin only for other methods.
+	permissions = map[string]map[string]access{
+		"POST": {"/users": public, "/users/refresh": public, "/users/logout": anyUser,
+			"/users/logout/all": anyUser, "/actors": editors, "/films": editors, "/genres": editors,
+			"/reviews": anyUser, "/watchlist": anyUser, "/collections": anyUser,
+			"/revisions/{entity}/{id:int}/{version:int}/rollback": editors},
+		"GET": {"/watchlist": anyUser, "/collections": anyUser, "/collections/mine": anyUser,
+			"/audit": admins, "/trash": admins, "/revisions/{entity}/{id:int}": editors,
+			"/revisions/{entity}/{id:int}/diff": editors},
+		"PUT": {"/actors": editors, "/films": editors, "/genres": editors, "/reviews": anyUser,
+			"/collections": anyUser, "/users": moderators},
+		"DELETE": {"/actors": admins, "/films": admins, "/genres": admins, "/reviews": anyUser,
+			"/actors/{id:int}": admins, "/films/{id:int}": admins, "/genres/{id:int}": admins,
+			"/reviews/moderate": moderators, "/reviews/{id:int}": anyUser, "/watchlist": anyUser,
+			"/watchlist/{film_id:int}": anyUser, "/collections": anyUser, "/users": anyUser},
 	}
+)
 
-	if len(headerParts[1]) == 0 {
-		r.sendErrorResponse(writer, http.StatusUnauthorized, "token is empty")
-		return
+func getAccess(method, route string) access {
+	if a, ok := permissions[method][route]; ok {
+		return a
 	}
+	if method == "GET" {
+		return public
+	}
+	return admins
+}
 
-	userId, err := r.service.User.ParseToken(headerParts[1])
-	if err != nil {
-		r.sendErrorResponse(writer, http.StatusUnauthorized, err.Error())
-		return
+// Middleware wraps a handler with a layer like authentication or logging. A layer that
+// rejects a request writes the response itself and does not call next.
+type Middleware func(next http.Handler) http.Handler
+
+func chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
 	}
+	return handler
+}
 
-	if ok, err := r.service.User.ValidateUser(userId); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
-		return
-	} else if !ok {
-		r.sendErrorResponse(writer, http.StatusLocked, "this function locked for current user")
-		return
+// accessChain turns an entry of the permissions table into the auth layers of a route.
+func (r *Router) accessChain(a access) []Middleware {
+	switch {
+	case a.public:
+		return nil
+	case a.anyUser:
+		return []Middleware{r.authenticate}
+	default:
+		return []Middleware{r.authenticate, r.requireRole(a)}
 	}
+}
+
+func (r *Router) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		header := request.Header.Get(authorizationHeader)
+		if header == "" {
+			r.sendErrorResponse(writer, http.StatusUnauthorized, "empty header")
+			return
+		}
+
+		headerParts := strings.Split(header, " ")
+		if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+			r.sendErrorResponse(writer, http.StatusUnauthorized, "invalid auth header")
+			return
+		}
+
+		if len(headerParts[1]) == 0 {
+			r.sendErrorResponse(writer, http.StatusUnauthorized, "token is empty")
+			return
+		}
+
+		userId, err := r.service.User.ParseToken(headerParts[1])
+		if err != nil {
+			r.sendError(writer, err)
+			return
+		}
 
-	*request = *request.Clone(context.WithValue(request.Context(), userCtx, userId))
-	return
+		ctx := context.WithValue(request.Context(), userCtx, userId)
+		next.ServeHTTP(writer, request.WithContext(context.WithValue(ctx, tokenCtx, headerParts[1])))
+	})
 }
 
-func userIdentity(r *Router, writer http.ResponseWriter, request *http.Request) {
-	header := request.Header.Get(authorizationHeader)
-	if header == "" {
-		r.sendErrorResponse(writer, http.StatusUnauthorized, "empty header")
-		return
-	}
+// requireRole has to run after authenticate, it lets through only the roles listed in a.
+func (r *Router) requireRole(a access) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			userId, err := getUserId(request)
+			if err != nil {
+				r.sendError(writer, err)
+				return
+			}
 
-	headerParts := strings.Split(header, " ")
-	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-		r.sendErrorResponse(writer, http.StatusUnauthorized, "invalid auth header")
-		return
-	}
+			role, err := r.service.User.GetUserRole(userId)
+			if err != nil {
+				r.sendError(writer, err)
+				return
+			}
+			if _, ok := a.roles[role]; !ok {
+				r.sendErrorResponse(writer, http.StatusForbidden, "this function locked for current user")
+				return
+			}
 
-	if len(headerParts[1]) == 0 {
-		r.sendErrorResponse(writer, http.StatusUnauthorized, "token is empty")
-		return
+			next.ServeHTTP(writer, request.WithContext(context.WithValue(request.Context(), roleCtx, role)))
+		})
 	}
+}
 
-	userId, err := r.service.User.ParseToken(headerParts[1])
-	if err != nil {
-		r.sendErrorResponse(writer, http.StatusUnauthorized, err.Error())
-		return
-	}
+// statusRecorder remembers the status written by the inner handlers for logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func logging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: writer, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, request)
 
-	*request = *request.Clone(context.WithValue(request.Context(), userCtx, userId))
+		logrus.WithFields(logrus.Fields{
+			"request_id": writer.Header().Get(requestIdHeader),
+			"method":     request.Method,
+			"uri":        request.RequestURI,
+			"status":     recorder.status,
+			"duration":   time.Since(start).String(),
+		}).Info("request handled")
+	})
+}
+
+// requestId takes the id of the request from the header or generates a new one and echoes
+// it in the response, so it can be found in the logs from an error response.
+func requestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		id := request.Header.Get(requestIdHeader)
+		if id == "" {
+			buf := make([]byte, 8)
+			if _, err := rand.Read(buf); err == nil {
+				id = hex.EncodeToString(buf)
+			}
+		}
+
+		writer.Header().Set(requestIdHeader, id)
+		next.ServeHTTP(writer, request)
+	})
+}
+
+func recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				logrus.Errorf("panic while serving %s %s: %v", request.Method, request.RequestURI, err)
+				writeError(writer, http.StatusInternalServerError,
+					filmoteka.NewError(filmoteka.CodeInternal, "internal server error"))
+			}
+		}()
+
+		next.ServeHTTP(writer, request)
+	})
 }
 
 func getUserId(r *http.Request) (int, error) {
@@ -82,3 +215,19 @@ func getUserId(r *http.Request) (int, error) {
 	}
 	return id.(int), nil
 }
+
+func getToken(r *http.Request) (string, error) {
+	token := r.Context().Value(tokenCtx)
+	if token == nil {
+		return "", errors.New("token not found")
+	}
+	return token.(string), nil
+}
+
+func getUserRole(r *http.Request) (string, error) {
+	role := r.Context().Value(roleCtx)
+	if role == nil {
+		return "", errors.New("user role not found")
+	}
+	return role.(string), nil
+}
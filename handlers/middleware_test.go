@@ -4,16 +4,19 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"github.com/jorgini/filmoteka"
 	"github.com/jorgini/filmoteka/service"
 	"github.com/jorgini/filmoteka/service/mocks"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 )
 
-func TestHandler_validation(t *testing.T) {
+func TestHandler_authenticate(t *testing.T) {
 	// Init Test Table
 	type mockBehavior func(r *mock_service.MockUser, token string)
 
@@ -33,7 +36,7 @@ func TestHandler_validation(t *testing.T) {
 			token:       "token",
 			mockBehavior: func(r *mock_service.MockUser, token string) {
 				r.EXPECT().ParseToken(token).Return(1, nil)
-				r.EXPECT().ValidateUser(1).Return(true, nil)
+				r.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: "",
@@ -45,7 +48,7 @@ func TestHandler_validation(t *testing.T) {
 			token:                "token",
 			mockBehavior:         func(r *mock_service.MockUser, token string) {},
 			expectedStatusCode:   401,
-			expectedResponseBody: `empty header`,
+			expectedResponseBody: `{"code":"unauthorized","message":"empty header"}`,
 		},
 		{
 			name:                 "Invalid Header Value",
@@ -54,7 +57,7 @@ func TestHandler_validation(t *testing.T) {
 			token:                "token",
 			mockBehavior:         func(r *mock_service.MockUser, token string) {},
 			expectedStatusCode:   401,
-			expectedResponseBody: `invalid auth header`,
+			expectedResponseBody: `{"code":"unauthorized","message":"invalid auth header"}`,
 		},
 		{
 			name:                 "Empty Token",
@@ -63,7 +66,7 @@ func TestHandler_validation(t *testing.T) {
 			token:                "token",
 			mockBehavior:         func(r *mock_service.MockUser, token string) {},
 			expectedStatusCode:   401,
-			expectedResponseBody: `token is empty`,
+			expectedResponseBody: `{"code":"unauthorized","message":"token is empty"}`,
 		},
 		{
 			name:        "Parse Error",
@@ -71,10 +74,10 @@ func TestHandler_validation(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(r *mock_service.MockUser, token string) {
-				r.EXPECT().ParseToken(token).Return(0, errors.New("invalid token"))
+				r.EXPECT().ParseToken(token).Return(0, filmoteka.UnauthorizedError("invalid token"))
 			},
 			expectedStatusCode:   401,
-			expectedResponseBody: `invalid token`,
+			expectedResponseBody: `{"code":"unauthorized","message":"invalid token"}`,
 		},
 		{
 			name:        "Not valid",
@@ -83,10 +86,10 @@ func TestHandler_validation(t *testing.T) {
 			token:       "token",
 			mockBehavior: func(r *mock_service.MockUser, token string) {
 				r.EXPECT().ParseToken(token).Return(1, nil)
-				r.EXPECT().ValidateUser(1).Return(false, nil)
+				r.EXPECT().GetUserRole(1).Return(filmoteka.RegularRole, nil)
 			},
-			expectedStatusCode:   423,
-			expectedResponseBody: `this function locked for current user`,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
 		},
 		{
 			name:        "Service error",
@@ -95,10 +98,10 @@ func TestHandler_validation(t *testing.T) {
 			token:       "token",
 			mockBehavior: func(r *mock_service.MockUser, token string) {
 				r.EXPECT().ParseToken(token).Return(1, nil)
-				r.EXPECT().ValidateUser(1).Return(false, errors.New("something went wrong"))
+				r.EXPECT().GetUserRole(1).Return("", errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -113,7 +116,8 @@ func TestHandler_validation(t *testing.T) {
 
 			services := &service.Service{User: repo}
 			handler := Router{service: services}
-			handler.AddEndPoint("GET", "/users", validation)
+			handler.AddEndPoint("GET", "/users", func(r *Router, w http.ResponseWriter, req *http.Request) {},
+				handler.authenticate, handler.requireRole(admins))
 
 			// Init Test Request
 			w := httptest.NewRecorder()
@@ -124,11 +128,162 @@ func TestHandler_validation(t *testing.T) {
 
 			// Asserts
 			assert.Equal(t, test.expectedStatusCode, w.Code)
-			assert.Equal(t, test.expectedResponseBody, w.Body.String())
+			assert.Equal(t, test.expectedResponseBody, strings.TrimSuffix(w.Body.String(), "\n"))
 		})
 	}
 }
 
+func TestRouter_permissions(t *testing.T) {
+	// Init Test Table
+	testTable := []struct {
+		name               string
+		method             string
+		route              string
+		role               string
+		expectedStatusCode int
+	}{
+		{name: "Editor creates film", method: "POST", route: "/films", role: filmoteka.EditorRole,
+			expectedStatusCode: 200},
+		{name: "Editor updates actor", method: "PUT", route: "/actors", role: filmoteka.EditorRole,
+			expectedStatusCode: 200},
+		{name: "Editor deletes film", method: "DELETE", route: "/films", role: filmoteka.EditorRole,
+			expectedStatusCode: 403},
+		{name: "Editor manages users", method: "PUT", route: "/users", role: filmoteka.EditorRole,
+			expectedStatusCode: 403},
+		{name: "Moderator manages users", method: "PUT", route: "/users", role: filmoteka.ModeratorRole,
+			expectedStatusCode: 200},
+		{name: "Moderator creates film", method: "POST", route: "/films", role: filmoteka.ModeratorRole,
+			expectedStatusCode: 403},
+		{name: "Regular moderates review", method: "DELETE", route: "/reviews/moderate",
+			role: filmoteka.RegularRole, expectedStatusCode: 403},
+		{name: "Admin deletes actor", method: "DELETE", route: "/actors", role: filmoteka.AdminRole,
+			expectedStatusCode: 200},
+		{name: "Unlisted endpoint", method: "PUT", route: "/smt", role: filmoteka.ModeratorRole,
+			expectedStatusCode: 403},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockUser(c)
+			repo.EXPECT().ParseToken("token").Return(1, nil)
+			repo.EXPECT().GetUserRole(1).Return(test.role, nil)
+
+			services := &service.Service{User: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint(test.method, test.route, func(r *Router, w http.ResponseWriter, req *http.Request) {})
+
+			// Init Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, test.route, nil)
+			req.Header.Set("Authorization", "Bearer token")
+
+			handler.ServeHTTP(w, req)
+
+			// Asserts
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestRouter_concurrentRequests(t *testing.T) {
+	// Init Dependencies
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_service.NewMockUser(c)
+	repo.EXPECT().ParseToken("good").Return(1, nil).AnyTimes()
+	repo.EXPECT().ParseToken("bad").Return(0, filmoteka.UnauthorizedError("invalid token")).AnyTimes()
+
+	services := &service.Service{User: repo}
+	handler := Router{service: services}
+	handler.AddEndPoint("DELETE", "/users", func(r *Router, w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	// Make Requests
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for _, token := range []string{"good", "bad"} {
+			wg.Add(1)
+			go func(token string) {
+				defer wg.Done()
+
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest("DELETE", "/users", nil)
+				req.Header.Set("Authorization", "Bearer "+token)
+				handler.ServeHTTP(w, req)
+
+				// A failed authentication of one request must never affect another one
+				if token == "good" {
+					assert.Equal(t, 200, w.Code)
+					assert.Equal(t, "ok", w.Body.String())
+				} else {
+					assert.Equal(t, 401, w.Code)
+					assert.Equal(t, `{"code":"unauthorized","message":"invalid token"}`+"\n", w.Body.String())
+				}
+			}(token)
+		}
+	}
+	wg.Wait()
+}
+
+func TestRouter_middlewareChain(t *testing.T) {
+	var order []string
+	layer := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, req)
+			})
+		}
+	}
+
+	handler := Router{}
+	handler.Use(recovery, logging, layer("global"))
+	handler.Group("/films").With(layer("group")).
+		AddEndPoint("GET", "/list", func(r *Router, w http.ResponseWriter, req *http.Request) {
+			order = append(order, "handler")
+		}, layer("route")).
+		AddEndPoint("GET", "/panic", func(r *Router, w http.ResponseWriter, req *http.Request) {
+			panic("something went wrong")
+		})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/films/list", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, []string{"global", "group", "route", "handler"}, order)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/films/panic", nil))
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, `{"code":"internal_error","message":"internal server error"}`+"\n", w.Body.String())
+}
+
+func TestRouter_requestId(t *testing.T) {
+	handler := Router{}
+	handler.Use(requestId)
+	handler.AddEndPoint("GET", "/films", func(r *Router, w http.ResponseWriter, req *http.Request) {
+		r.sendErrorResponse(w, http.StatusBadRequest, "page out of bounds")
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/films", nil)
+	req.Header.Set(requestIdHeader, "f00d")
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, "f00d", w.Header().Get(requestIdHeader))
+	assert.Equal(t, `{"code":"validation_error","message":"page out of bounds","request_id":"f00d"}`+"\n",
+		w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/films", nil))
+	assert.Len(t, w.Header().Get(requestIdHeader), 16)
+}
+
 func TestGetUserId(t *testing.T) {
 	var getContext = func(id int) context.Context {
 		ctx := context.WithValue(context.Background(), userCtx, id)
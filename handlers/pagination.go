@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"github.com/jorgini/filmoteka"
+	"net/http"
+	"strconv"
+)
+
+const (
+	limitOnPage        = 10
+	defaultMaxPageSize = 100
+)
+
+// getPageParams reads the page number and the page size of a list request. The first page
+// and limitOnPage items are used when they are not given, a bigger size than the
+// configured maximum is cut down to it.
+func (r *Router) getPageParams(request *http.Request) (int, int, error) {
+	page, size := 1, limitOnPage
+
+	if value := request.URL.Query().Get("page"); value != "" {
+		var err error
+		if page, err = strconv.Atoi(value); err != nil {
+			return 0, 0, filmoteka.ValidationError("invalid page number",
+				map[string]string{"page": "expected int"})
+		}
+		if page < 1 {
+			return 0, 0, filmoteka.ValidationError("page out of bounds", nil)
+		}
+	}
+
+	if value := request.URL.Query().Get("page_size"); value != "" {
+		var err error
+		if size, err = strconv.Atoi(value); err != nil || size < 1 {
+			return 0, 0, filmoteka.ValidationError("invalid page size",
+				map[string]string{"page_size": "expected positive int"})
+		}
+	}
+
+	maxSize := r.maxPageSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxPageSize
+	}
+	if size > maxSize {
+		size = maxSize
+	}
+	return page, size, nil
+}
+
+// newPage wraps items into the list envelope with links to the neighbour pages. A page
+// past the end is not an error, it is just empty.
+func newPage[T any](request *http.Request, items []T, page, size, total int) filmoteka.Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	result := filmoteka.Page[T]{Items: items, Page: page, PageSize: size, Total: total}
+	if page*size < total {
+		result.Next = pageLink(request, page+1, size, 0)
+	}
+	if page > 1 {
+		result.Prev = pageLink(request, page-1, size, 0)
+	}
+	return result
+}
+
+// pageLink repeats the request with another page. When after is set the link continues
+// the list right after the item with this id instead of skipping the previous pages, such
+// a link has no page number.
+func pageLink(request *http.Request, page, size, after int) string {
+	query := request.URL.Query()
+	query.Set("page_size", strconv.Itoa(size))
+	if after != 0 {
+		query.Del("page")
+		query.Set("after", strconv.Itoa(after))
+	} else {
+		query.Set("page", strconv.Itoa(page))
+		query.Del("after")
+	}
+	return request.URL.Path + "?" + query.Encode()
+}
+
+// getSortParam reads the sort specification of a list request like "-rating,title", the
+// spec def is used when it is not given. No keys at all leave the list ordered by id.
+func getSortParam(request *http.Request, allowed map[string]struct{}, def string) ([]filmoteka.SortKey, error) {
+	spec := request.URL.Query().Get("sort")
+	if spec == "" {
+		spec = def
+	}
+	if spec == "" {
+		return nil, nil
+	}
+	return filmoteka.ParseSort(spec, allowed)
+}
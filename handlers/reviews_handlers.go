@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+func createNewReview(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	var review filmoteka.Review
+	if err := parseBody(request.Body, &review); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	review.UserId = id
+
+	reviewId, err := r.service.Review.CreateReview(review)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, fmt.Sprintf("successfully create review with id %d", reviewId)); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("new review with id %d for film with id %d was created by user with id %d",
+		reviewId, review.FilmId, id)
+}
+
+func updateReview(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	reviewId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "no id specified to update review")
+		return
+	}
+
+	var update filmoteka.UpdateReviewInput
+	if err = parseBody(request.Body, &update); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = r.service.Review.UpdateReview(reviewId, id, update); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully update"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("review with id %d was updated by user with id %d", reviewId, id)
+}
+
+func getFilmReviews(r *Router, writer http.ResponseWriter, request *http.Request) {
+	filmId, err := getIntParam(request, "film_id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "film id for reviews not specified")
+		return
+	}
+
+	page, size, err := r.getPageParams(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	reviews, total, err := r.service.Review.GetFilmReviews(filmId, page, size)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, newPage(request, reviews, page, size, total)); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("reviews of film with id %d were sent to user", filmId)
+}
+
+func deleteReview(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	reviewId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id doesnt specified to delete review")
+		return
+	}
+
+	if err = r.service.Review.DeleteReview(reviewId, id); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully delete"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("review with id %d was deleted by user with id %d", reviewId, id)
+}
+
+func moderateReview(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	reviewId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id doesnt specified to delete review")
+		return
+	}
+
+	if err = r.service.Review.ModerateReview(reviewId); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully delete"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("review with id %d was removed by moderator with id %d", reviewId, id)
+}
@@ -0,0 +1,487 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/service"
+	"github.com/jorgini/filmoteka/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter_createNewReview(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.Review)
+
+	var (
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+	)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		inputReview          filmoteka.Review
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Ok",
+			inputBody:   `{"film_id": 1, "rating": 8, "text": "good"}`,
+			inputReview: filmoteka.Review{UserId: userId, FilmId: 1, Rating: 8, Text: "good"},
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.Review) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().CreateReview(review).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully create review with id 1"`,
+		},
+		{
+			name:        "Wrong Input",
+			inputBody:   `{"film_id": 1, "rating": 11}`,
+			inputReview: filmoteka.Review{},
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.Review) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required field(s)"}`,
+		},
+		{
+			name:        "Unauthorized",
+			inputBody:   `{"film_id": 1, "rating": 8}`,
+			inputReview: filmoteka.Review{},
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.Review) {
+				r2.EXPECT().ParseToken(token).Return(0, filmoteka.UnauthorizedError("invalid token"))
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"code":"unauthorized","message":"invalid token"}`,
+		},
+		{
+			name:        "Service Error",
+			inputBody:   `{"film_id": 1, "rating": 8}`,
+			inputReview: filmoteka.Review{UserId: userId, FilmId: 1, Rating: 8},
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.Review) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().CreateReview(review).Return(0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockReview(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2, test.inputReview)
+
+			services := &service.Service{Review: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("POST", "/reviews", createNewReview)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/reviews",
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_updateReview(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.UpdateReviewInput)
+
+	var (
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+		reviewId    = 1
+		rating      = 6
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		inputBody            string
+		inputReview          filmoteka.UpdateReviewInput
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Ok",
+			params:      "id=1",
+			inputBody:   `{"rating": 6}`,
+			inputReview: filmoteka.UpdateReviewInput{Rating: &rating},
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.UpdateReviewInput) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().UpdateReview(reviewId, userId, review).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully update"`,
+		},
+		{
+			name:        "Wrong Params",
+			params:      "id=fafmek",
+			inputBody:   `{"rating": 6}`,
+			inputReview: filmoteka.UpdateReviewInput{},
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.UpdateReviewInput) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"no id specified to update review"}`,
+		},
+		{
+			name:        "Wrong Input",
+			params:      "id=1",
+			inputBody:   `{}`,
+			inputReview: filmoteka.UpdateReviewInput{},
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.UpdateReviewInput) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required filed to update review"}`,
+		},
+		{
+			name:        "Service Error",
+			params:      "id=1",
+			inputBody:   `{"rating": 6}`,
+			inputReview: filmoteka.UpdateReviewInput{Rating: &rating},
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser, review filmoteka.UpdateReviewInput) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().UpdateReview(reviewId, userId, review).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockReview(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2, test.inputReview)
+
+			services := &service.Service{Review: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("PUT", "/reviews", updateReview)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/reviews?"+test.params,
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_getFilmReviews(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockReview)
+
+	var (
+		date    = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		limit   = 10
+		reviews = []filmoteka.Review{{
+			Id:        1,
+			UserId:    2,
+			FilmId:    1,
+			Rating:    8,
+			Text:      "good",
+			CreatedAt: date,
+			UpdatedAt: date,
+		}}
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "film_id=1&page=1",
+			mockBehavior: func(r *mock_service.MockReview) {
+				r.EXPECT().GetFilmReviews(1, 1, limit).Return(reviews, 11, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[{"id":1,"user_id":2,"film_id":1,"rating":8,"text":"good",` +
+				`"created_at":"2024-03-01T12:00:00Z","updated_at":"2024-03-01T12:00:00Z"}],"page":1,"page_size":10,` +
+				`"total":11,"next":"/reviews/list?film_id=1\u0026page=2\u0026page_size=10"}`,
+		},
+		{
+			name:   "Default Page",
+			params: "film_id=1&page_size=5",
+			mockBehavior: func(r *mock_service.MockReview) {
+				r.EXPECT().GetFilmReviews(1, 1, 5).Return(nil, 0, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":1,"page_size":5,"total":0}`,
+		},
+		{
+			name:                 "Wrong Params",
+			params:               "page=1",
+			mockBehavior:         func(r *mock_service.MockReview) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"film id for reviews not specified"}`,
+		},
+		{
+			name:                 "Wrong Page",
+			params:               "film_id=1&page=0",
+			mockBehavior:         func(r *mock_service.MockReview) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"page out of bounds"}`,
+		},
+		{
+			name:   "Over page",
+			params: "film_id=1&page=2",
+			mockBehavior: func(r *mock_service.MockReview) {
+				r.EXPECT().GetFilmReviews(1, 2, limit).Return([]filmoteka.Review{}, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/reviews/list?film_id=1\u0026page=1\u0026page_size=10"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "film_id=1&page=1",
+			mockBehavior: func(r *mock_service.MockReview) {
+				r.EXPECT().GetFilmReviews(1, 1, limit).Return(nil, 0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockReview(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{Review: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/reviews/list", getFilmReviews)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/reviews/list?"+test.params, bytes.NewBufferString(""))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_deleteReview(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockReview, r2 *mock_service.MockUser)
+
+	var (
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+		reviewId    = 1
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().DeleteReview(reviewId, userId).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully delete"`,
+		},
+		{
+			name:   "Wrong Params",
+			params: "id=fksfm",
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"id doesnt specified to delete review"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().DeleteReview(reviewId, userId).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockReview(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2)
+
+			services := &service.Service{Review: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("DELETE", "/reviews", deleteReview)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/reviews?"+test.params,
+				bytes.NewBufferString(""))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_moderateReview(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockReview, r2 *mock_service.MockUser)
+
+	var (
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+		reviewId    = 1
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.ModeratorRole, nil)
+				r1.EXPECT().ModerateReview(reviewId).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully delete"`,
+		},
+		{
+			name:   "Locked",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.EditorRole, nil)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
+		},
+		{
+			name:   "Wrong Params",
+			params: "id=fksfm",
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"id doesnt specified to delete review"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "id=1",
+			mockBehavior: func(r1 *mock_service.MockReview, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r2.EXPECT().GetUserRole(userId).Return(filmoteka.ModeratorRole, nil)
+				r1.EXPECT().ModerateReview(reviewId).Return(errors.New("review not found"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockReview(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2)
+
+			services := &service.Service{Review: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("DELETE", "/reviews/moderate", moderateReview)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/reviews/moderate?"+test.params,
+				bytes.NewBufferString(""))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
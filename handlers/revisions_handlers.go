@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+var revisionEntities = map[string]struct{}{filmoteka.AuditFilm: {}, filmoteka.AuditActor: {}}
+
+// getRevisionTarget reads the entity and its id from the path, only films and actors keep
+// their revisions.
+func getRevisionTarget(request *http.Request) (string, int, error) {
+	entity := getParam(request, "entity")
+	if _, ok := revisionEntities[entity]; !ok {
+		return "", 0, filmoteka.ValidationError("invalid entity", map[string]string{"entity": "expected film or actor"})
+	}
+
+	id, err := getIntParam(request, "id")
+	if err != nil || id < 1 {
+		return "", 0, filmoteka.ValidationError("invalid id", map[string]string{"id": "expected positive int"})
+	}
+	return entity, id, nil
+}
+
+// getVersionQuery reads a required version of a revision from the query.
+func getVersionQuery(request *http.Request, name string) (int, error) {
+	version, err := getIdQuery(request.URL.Query(), name)
+	if err != nil {
+		return 0, err
+	}
+	if version == nil {
+		return 0, filmoteka.ValidationError(name+" not specified", map[string]string{name: "expected positive int"})
+	}
+	return *version, nil
+}
+
+func getRevisions(r *Router, writer http.ResponseWriter, request *http.Request) {
+	page, size, err := r.getPageParams(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	entity, id, err := getRevisionTarget(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	revisions, total, err := r.service.Revision.GetRevisions(entity, id, page, size)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, newPage(request, revisions, page, size, total)); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("revisions of %s with id %d in page %d were sent to user", entity, id, page)
+}
+
+func diffRevisions(r *Router, writer http.ResponseWriter, request *http.Request) {
+	entity, id, err := getRevisionTarget(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	from, err := getVersionQuery(request, "from")
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	to, err := getVersionQuery(request, "to")
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	diff, err := r.service.Revision.DiffRevisions(entity, id, from, to)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, diff); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("diff of revisions %d and %d of %s with id %d was sent to user", from, to, entity, id)
+}
+
+func rollbackRevision(r *Router, writer http.ResponseWriter, request *http.Request) {
+	userId, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	entity, id, err := getRevisionTarget(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	version, err := getIntParam(request, "version")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "version doesnt specified to rollback")
+		return
+	}
+
+	if err = r.service.Revision.Rollback(userId, entity, id, version); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, fmt.Sprintf("successfully rollback %s with id %d to revision %d", entity, id, version)); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("%s with id %d has been rolled back to revision %d by user with id %d", entity, id, version, userId)
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/service"
+	"github.com/jorgini/filmoteka/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter_getRevisions(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser)
+
+	const token = "fmekwfmw"
+
+	var (
+		userId    = 2
+		revisions = []filmoteka.Revision{{
+			Entity:    filmoteka.AuditFilm,
+			EntityId:  3,
+			Version:   2,
+			UserId:    &userId,
+			Snapshot:  json.RawMessage(`{"title":"new","cast":[]}`),
+			CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}}
+	)
+
+	tests := []struct {
+		name                 string
+		path                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			path: "/revisions/film/3",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.EditorRole, nil)
+				r1.EXPECT().GetRevisions(filmoteka.AuditFilm, 3, 1, 10).Return(revisions, 2, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[{"entity":"film","entity_id":3,"version":2,"user_id":2,` +
+				`"snapshot":{"title":"new","cast":[]},"created_at":"2024-01-01T00:00:00Z"}],` +
+				`"page":1,"page_size":10,"total":2}`,
+		},
+		{
+			name: "Locked",
+			path: "/revisions/actor/3",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.RegularRole, nil)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
+		},
+		{
+			name: "Wrong Entity",
+			path: "/revisions/user/3",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.EditorRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid entity","details":{"entity":"expected film or actor"}}`,
+		},
+		{
+			name: "Service Error",
+			path: "/revisions/actor/3",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.EditorRole, nil)
+				r1.EXPECT().GetRevisions(filmoteka.AuditActor, 3, 1, 10).Return(nil, 0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRevision(c)
+			user := mock_service.NewMockUser(c)
+			test.mockBehavior(repo, user)
+
+			services := &service.Service{Revision: repo, User: user}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/revisions/{entity}/{id:int}", getRevisions)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", test.path, bytes.NewBufferString(""))
+			req.Header.Set(authorizationHeader, fmt.Sprintf("Bearer %s", token))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_diffRevisions(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser)
+
+	const token = "fmekwfmw"
+
+	diff := filmoteka.RevisionDiff{
+		Entity:   filmoteka.AuditFilm,
+		EntityId: 3,
+		From:     1,
+		To:       2,
+		Changes: []filmoteka.FieldChange{
+			{Field: "cast", From: json.RawMessage(`[]`), To: json.RawMessage(`[{"actor_id":1}]`)},
+			{Field: "description", From: json.RawMessage(`"old"`), To: json.RawMessage(`"new"`)},
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "from=1&to=2",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().DiffRevisions(filmoteka.AuditFilm, 3, 1, 2).Return(diff, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"entity":"film","entity_id":3,"from":1,"to":2,"changes":[` +
+				`{"field":"cast","from":[],"to":[{"actor_id":1}]},{"field":"description","from":"old","to":"new"}]}`,
+		},
+		{
+			name:   "No To",
+			params: "from=1",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"to not specified","details":{"to":"expected positive int"}}`,
+		},
+		{
+			name:   "Wrong From",
+			params: "from=first&to=2",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid from","details":{"from":"expected positive int"}}`,
+		},
+		{
+			name:   "Revision Not Found",
+			params: "from=1&to=7",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().DiffRevisions(filmoteka.AuditFilm, 3, 1, 7).Return(filmoteka.RevisionDiff{},
+					filmoteka.NotFoundError("requested record not found"))
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"not_found","message":"requested record not found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRevision(c)
+			user := mock_service.NewMockUser(c)
+			test.mockBehavior(repo, user)
+
+			services := &service.Service{Revision: repo, User: user}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/revisions/{entity}/{id:int}/diff", diffRevisions)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/revisions/film/3/diff?"+test.params, bytes.NewBufferString(""))
+			req.Header.Set(authorizationHeader, fmt.Sprintf("Bearer %s", token))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_rollbackRevision(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser)
+
+	const token = "fmekwfmw"
+
+	tests := []struct {
+		name                 string
+		path                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			path: "/revisions/actor/3/1/rollback",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.EditorRole, nil)
+				r1.EXPECT().Rollback(1, filmoteka.AuditActor, 3, 1).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully rollback actor with id 3 to revision 1"`,
+		},
+		{
+			name: "Locked",
+			path: "/revisions/film/3/1/rollback",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.ModeratorRole, nil)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
+		},
+		{
+			name: "Deleted Film",
+			path: "/revisions/film/3/1/rollback",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.EditorRole, nil)
+				r1.EXPECT().Rollback(1, filmoteka.AuditFilm, 3, 1).Return(filmoteka.NotFoundError("film not found"))
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"not_found","message":"film not found"}`,
+		},
+		{
+			name: "Wrong Entity",
+			path: "/revisions/genre/3/1/rollback",
+			mockBehavior: func(r1 *mock_service.MockRevision, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.EditorRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid entity","details":{"entity":"expected film or actor"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRevision(c)
+			user := mock_service.NewMockUser(c)
+			test.mockBehavior(repo, user)
+
+			services := &service.Service{Revision: repo, User: user}
+			handler := Router{service: services}
+			handler.AddEndPoint("POST", "/revisions/{entity}/{id:int}/{version:int}/rollback", rollbackRevision)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", test.path, bytes.NewBufferString(""))
+			req.Header.Set(authorizationHeader, fmt.Sprintf("Bearer %s", token))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const paramsCtx = "params"
+
+// segment is a part of a route pattern, either a literal or a {name} / {name:int} parameter.
+type segment struct {
+	value string
+	param bool
+	isInt bool
+}
+
+type route struct {
+	pattern  string
+	segments []segment
+	params   bool
+	methods  map[string]http.Handler
+}
+
+func parsePattern(pattern string) []segment {
+	parts := strings.Split(pattern, "/")
+	segments := make([]segment, len(parts))
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+			segments[i] = segment{value: part}
+			continue
+		}
+
+		name, kind, _ := strings.Cut(part[1:len(part)-1], ":")
+		if name == "" || (kind != "" && kind != "int") {
+			panic(fmt.Sprintf("invalid path parameter %s in route %s", part, pattern))
+		}
+		segments[i] = segment{value: name, param: true, isInt: kind == "int"}
+	}
+	return segments
+}
+
+func (rt *route) match(parts []string) (map[string]string, bool) {
+	if len(parts) != len(rt.segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, seg := range rt.segments {
+		if !seg.param {
+			if seg.value != parts[i] {
+				return nil, false
+			}
+			continue
+		}
+
+		if seg.isInt {
+			if _, err := strconv.Atoi(parts[i]); err != nil {
+				return nil, false
+			}
+		}
+		params[seg.value] = parts[i]
+	}
+	return params, true
+}
+
+// allow lists the methods of the route for the Allow header, HEAD and OPTIONS are served
+// automatically.
+func (rt *route) allow() string {
+	methods := make([]string, 0, len(rt.methods)+2)
+	for method := range rt.methods {
+		methods = append(methods, method)
+	}
+	if _, ok := rt.methods["GET"]; ok {
+		if _, ok := rt.methods["HEAD"]; !ok {
+			methods = append(methods, "HEAD")
+		}
+	}
+	if _, ok := rt.methods["OPTIONS"]; !ok {
+		methods = append(methods, "OPTIONS")
+	}
+
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
+// findRoute prefers a literal route and falls back to the pattern routes in the order of
+// their registration.
+func (r *Router) findRoute(path string) (*route, map[string]string) {
+	if rt, ok := r.routes[path]; ok && !rt.params {
+		return rt, nil
+	}
+
+	parts := strings.Split(path, "/")
+	for _, rt := range r.patterns {
+		if params, ok := rt.match(parts); ok {
+			return rt, params
+		}
+	}
+	return nil, nil
+}
+
+// RouteGroup registers endpoints under a common path prefix with common middlewares.
+type RouteGroup struct {
+	router      *Router
+	prefix      string
+	middlewares []Middleware
+}
+
+func (r *Router) Group(prefix string) *RouteGroup {
+	return &RouteGroup{router: r, prefix: prefix}
+}
+
+func (g *RouteGroup) Group(prefix string) *RouteGroup {
+	return &RouteGroup{router: g.router, prefix: g.prefix + prefix, middlewares: g.middlewares}
+}
+
+// With returns a copy of the group whose endpoints are additionally wrapped by middlewares.
+func (g *RouteGroup) With(middlewares ...Middleware) *RouteGroup {
+	layers := append(append([]Middleware{}, g.middlewares...), middlewares...)
+	return &RouteGroup{router: g.router, prefix: g.prefix, middlewares: layers}
+}
+
+func (g *RouteGroup) AddEndPoint(method, path string, handle func(r *Router, writer http.ResponseWriter,
+	request *http.Request), middlewares ...Middleware) *RouteGroup {
+	layers := append(append([]Middleware{}, g.middlewares...), middlewares...)
+	g.router.AddEndPoint(method, g.prefix+path, handle, layers...)
+	return g
+}
+
+// headResponseWriter drops the body written by a GET handler serving a HEAD request.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func withParams(request *http.Request, params map[string]string) *http.Request {
+	if len(params) == 0 {
+		return request
+	}
+	return request.WithContext(context.WithValue(request.Context(), paramsCtx, params))
+}
+
+// getIntParam reads an integer from the path parameters and falls back to the query string,
+// so the same handler serves both /films/42 and /films?id=42.
+func getIntParam(request *http.Request, name string) (int, error) {
+	if params, ok := request.Context().Value(paramsCtx).(map[string]string); ok {
+		if value, ok := params[name]; ok {
+			return strconv.Atoi(value)
+		}
+	}
+
+	value := request.URL.Query().Get(name)
+	if value == "" {
+		return 0, fmt.Errorf("parameter %s not specified", name)
+	}
+	return strconv.Atoi(value)
+}
+
+// getParam reads a path parameter and falls back to the query string like getIntParam.
+func getParam(request *http.Request, name string) string {
+	if params, ok := request.Context().Value(paramsCtx).(map[string]string); ok {
+		if value, ok := params[name]; ok {
+			return value
+		}
+	}
+	return request.URL.Query().Get(name)
+}
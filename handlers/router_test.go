@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_routing(t *testing.T) {
+	// Init Router
+	echo := func(r *Router, writer http.ResponseWriter, request *http.Request) {
+		id, err := getIntParam(request, "id")
+		if err != nil {
+			r.sendError(writer, err)
+			return
+		}
+		_, _ = fmt.Fprintf(writer, "film %d", id)
+	}
+	list := func(r *Router, writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte("list"))
+	}
+
+	handler := Router{}
+	handler.Group("/films").
+		AddEndPoint("GET", "", echo).
+		AddEndPoint("GET", "/list", list).
+		AddEndPoint("GET", "/{id:int}", echo).
+		AddEndPoint("GET", "/{id:int}/cast", echo).
+		Group("/{id:int}/names").AddEndPoint("GET", "/{name}", list)
+
+	// Init Test Table
+	testTable := []struct {
+		name                 string
+		method               string
+		target               string
+		expectedStatusCode   int
+		expectedAllow        string
+		expectedResponseBody string
+	}{
+		{name: "Query param", method: "GET", target: "/films?id=3", expectedStatusCode: 200,
+			expectedResponseBody: "film 3"},
+		{name: "Path param", method: "GET", target: "/films/42", expectedStatusCode: 200,
+			expectedResponseBody: "film 42"},
+		{name: "Nested path param", method: "GET", target: "/films/42/cast", expectedStatusCode: 200,
+			expectedResponseBody: "film 42"},
+		{name: "Literal before pattern", method: "GET", target: "/films/list", expectedStatusCode: 200,
+			expectedResponseBody: "list"},
+		{name: "Group in group", method: "GET", target: "/films/42/names/smt", expectedStatusCode: 200,
+			expectedResponseBody: "list"},
+		{name: "Wrong param type", method: "GET", target: "/films/abc", expectedStatusCode: 404,
+			expectedResponseBody: `{"code":"not_found","message":"this uri not found /films/abc"}` + "\n"},
+		{name: "Unknown path with unknown method", method: "PATCH", target: "/smt", expectedStatusCode: 404,
+			expectedResponseBody: `{"code":"not_found","message":"this uri not found /smt"}` + "\n"},
+		{name: "Method not allowed", method: "PATCH", target: "/films/42", expectedStatusCode: 405,
+			expectedAllow: "GET, HEAD, OPTIONS", expectedResponseBody: `{"code":"method_not_allowed","message":"this method not provided"}` + "\n"},
+		{name: "Head", method: "HEAD", target: "/films/42", expectedStatusCode: 200},
+		{name: "Options", method: "OPTIONS", target: "/films/42/cast", expectedStatusCode: 204,
+			expectedAllow: "GET, HEAD, OPTIONS"},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, test.target, nil)
+
+			handler.ServeHTTP(w, req)
+
+			// Asserts
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedAllow, w.Header().Get("Allow"))
+			assert.Equal(t, test.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+	assert.Panics(t, func() { parsePattern("/films/{id:float}") })
+	assert.Panics(t, func() { parsePattern("/films/{}") })
+	assert.Equal(t, []segment{{value: ""}, {value: "films"}, {value: "id", param: true, isInt: true}},
+		parsePattern("/films/{id:int}"))
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"github.com/jorgini/filmoteka"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultSuggestions = 10
+	maxSuggestions     = 20
+	maxPrefixLength    = 100
+)
+
+// getSuggestions serves the search box typeahead: the films and the actors starting with
+// the prefix in q, in one list with the best matches first.
+func getSuggestions(r *Router, writer http.ResponseWriter, request *http.Request) {
+	prefix := strings.TrimSpace(request.URL.Query().Get("q"))
+	if prefix == "" {
+		r.sendError(writer, filmoteka.ValidationError("prefix for suggestions not specified",
+			map[string]string{"q": "expected non-empty string"}))
+		return
+	}
+	if utf8.RuneCountInString(prefix) > maxPrefixLength {
+		r.sendError(writer, filmoteka.ValidationError("prefix for suggestions is too long",
+			map[string]string{"q": "expected at most " + strconv.Itoa(maxPrefixLength) + " characters"}))
+		return
+	}
+
+	limit := defaultSuggestions
+	if value := request.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			r.sendError(writer, filmoteka.ValidationError("invalid limit of suggestions",
+				map[string]string{"limit": "expected positive int"}))
+			return
+		}
+		if limit > maxSuggestions {
+			limit = maxSuggestions
+		}
+	}
+
+	suggestions, err := r.service.Suggestion.GetSuggestions(prefix, limit)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, suggestions); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("%d suggestions were sent to user", len(suggestions))
+}
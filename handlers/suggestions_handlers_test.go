@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/service"
+	"github.com/jorgini/filmoteka/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouter_getSuggestions(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockSuggestion, prefix string, limit int)
+
+	var (
+		suggestions = []filmoteka.Suggestion{
+			{Type: filmoteka.SuggestionFilm, Id: 3, Text: "Star Wars"},
+			{Type: filmoteka.SuggestionActor, Id: 5, Text: "Ringo Starr"},
+		}
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		prefix               string
+		limit                int
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "q=star",
+			prefix: "star",
+			limit:  10,
+			mockBehavior: func(r *mock_service.MockSuggestion, prefix string, limit int) {
+				r.EXPECT().GetSuggestions(prefix, limit).Return(suggestions, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"type":"film","id":3,"text":"Star Wars"},{"type":"actor","id":5,"text":"Ringo Starr"}]`,
+		},
+		{
+			name:   "Ok max limit",
+			params: "q=%20star%25&limit=100",
+			prefix: "star%",
+			limit:  20,
+			mockBehavior: func(r *mock_service.MockSuggestion, prefix string, limit int) {
+				r.EXPECT().GetSuggestions(prefix, limit).Return([]filmoteka.Suggestion{}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:                 "Empty Prefix",
+			params:               "q=%20",
+			mockBehavior:         func(r *mock_service.MockSuggestion, prefix string, limit int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"prefix for suggestions not specified","details":{"q":"expected non-empty string"}}`,
+		},
+		{
+			name:                 "Long Prefix",
+			params:               "q=" + strings.Repeat("a", 101),
+			mockBehavior:         func(r *mock_service.MockSuggestion, prefix string, limit int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"prefix for suggestions is too long","details":{"q":"expected at most 100 characters"}}`,
+		},
+		{
+			name:                 "Wrong Limit",
+			params:               "q=star&limit=0",
+			mockBehavior:         func(r *mock_service.MockSuggestion, prefix string, limit int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid limit of suggestions","details":{"limit":"expected positive int"}}`,
+		},
+		{
+			name:   "Service Error",
+			params: "q=star",
+			prefix: "star",
+			limit:  10,
+			mockBehavior: func(r *mock_service.MockSuggestion, prefix string, limit int) {
+				r.EXPECT().GetSuggestions(prefix, limit).Return(nil, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockSuggestion(c)
+			test.mockBehavior(repo, test.prefix, test.limit)
+
+			services := &service.Service{Suggestion: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/search/suggest", getSuggestions)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/search/suggest?"+test.params, bytes.NewBufferString(""))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// getTrashEntity checks that the entity may be kept in the trash, the same ones are audited.
+func getTrashEntity(value string) error {
+	if _, ok := auditEntities[value]; !ok {
+		return filmoteka.ValidationError("invalid entity", map[string]string{"entity": "expected film, actor or user"})
+	}
+	return nil
+}
+
+func getDeletedList(r *Router, writer http.ResponseWriter, request *http.Request) {
+	page, size, err := r.getPageParams(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	var entity *string
+	if value := request.URL.Query().Get("entity"); value != "" {
+		if err := getTrashEntity(value); err != nil {
+			r.sendError(writer, err)
+			return
+		}
+		entity = &value
+	}
+
+	deleted, total, err := r.service.Trash.GetDeleted(entity, page, size)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, newPage(request, deleted, page, size, total)); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("deleted entities in page %d were sent to admin", page)
+}
+
+func restoreDeleted(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	entity := getParam(request, "entity")
+	if err := getTrashEntity(entity); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	entityId, err := getIntParam(request, "id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "id doesnt specified to restore")
+		return
+	}
+
+	if err = r.service.Trash.Restore(id, entity, entityId); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, fmt.Sprintf("successfully restore %s with id %d", entity, entityId)); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("%s with id %d has been restored by user with id %d", entity, entityId, id)
+}
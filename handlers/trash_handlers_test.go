@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/service"
+	"github.com/jorgini/filmoteka/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter_getDeletedList(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser, entity *string)
+
+	const token = "fmekwfmw"
+
+	var (
+		film    = filmoteka.AuditFilm
+		deleted = []filmoteka.DeletedEntity{{
+			Entity:    filmoteka.AuditFilm,
+			Id:        3,
+			Name:      "Film",
+			DeletedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}}
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		entity               *string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "entity=film",
+			entity: &film,
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser, entity *string) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().GetDeleted(entity, 1, 10).Return(deleted, 1, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[{"entity":"film","id":3,"name":"Film","deleted_at":"2024-01-01T00:00:00Z"}],` +
+				`"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:   "Ok All Entities",
+			params: "page=2",
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser, entity *string) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().GetDeleted(entity, 2, 10).Return([]filmoteka.DeletedEntity{}, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/trash?page=1\u0026page_size=10"}`,
+		},
+		{
+			name:   "Locked",
+			params: "",
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser, entity *string) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.EditorRole, nil)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
+		},
+		{
+			name:   "Wrong Entity",
+			params: "entity=genre",
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser, entity *string) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid entity","details":{"entity":"expected film, actor or user"}}`,
+		},
+		{
+			name:   "Service Error",
+			params: "",
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser, entity *string) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().GetDeleted(entity, 1, 10).Return(nil, 0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockTrash(c)
+			user := mock_service.NewMockUser(c)
+			test.mockBehavior(repo, user, test.entity)
+
+			services := &service.Service{Trash: repo, User: user}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/trash", getDeletedList)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/trash?"+test.params, bytes.NewBufferString(""))
+			req.Header.Set(authorizationHeader, fmt.Sprintf("Bearer %s", token))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_restoreDeleted(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser)
+
+	const token = "fmekwfmw"
+
+	tests := []struct {
+		name                 string
+		path                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			path: "/trash/film/3/restore",
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().Restore(1, filmoteka.AuditFilm, 3).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully restore film with id 3"`,
+		},
+		{
+			name: "Locked",
+			path: "/trash/actor/3/restore",
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.ModeratorRole, nil)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
+		},
+		{
+			name: "Wrong Entity",
+			path: "/trash/review/3/restore",
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid entity","details":{"entity":"expected film, actor or user"}}`,
+		},
+		{
+			name: "Not In Trash",
+			path: "/trash/user/3/restore",
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().Restore(1, filmoteka.AuditUser, 3).Return(filmoteka.NotFoundError("deleted user not found"))
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"not_found","message":"deleted user not found"}`,
+		},
+		{
+			name: "Name Taken",
+			path: "/trash/actor/3/restore",
+			mockBehavior: func(r1 *mock_service.MockTrash, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(1, nil)
+				r2.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r1.EXPECT().Restore(1, filmoteka.AuditActor, 3).Return(filmoteka.ConflictError("record already exists",
+					map[string]string{"name, surname": "already exists"}))
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: `{"code":"conflict","message":"record already exists","details":{"name, surname":"already exists"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockTrash(c)
+			user := mock_service.NewMockUser(c)
+			test.mockBehavior(repo, user)
+
+			services := &service.Service{Trash: repo, User: user}
+			handler := Router{service: services}
+			handler.AddEndPoint("POST", "/trash/{entity}/{id:int}/restore", restoreDeleted)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", test.path, bytes.NewBufferString(""))
+			req.Header.Set(authorizationHeader, fmt.Sprintf("Bearer %s", token))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
@@ -3,31 +3,37 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/jorgini/filmoteka"
 	"github.com/sirupsen/logrus"
+	"io"
 	"net/http"
 )
 
 func createNewUser(r *Router, writer http.ResponseWriter, request *http.Request) {
 	var user filmoteka.User
 	if err := parseBody(request.Body, &user); err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	id, err := r.service.User.CreateUser(user)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	if err = writeBody(writer, "successful"); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 	}
 
 	logrus.Infof("a new user with an id %d has been registered", id)
 }
 
+// maxSignInPasswordLength bounds the password of a sign in. Legacy SHA-1 passwords may be
+// longer than filmoteka.MaxPasswordLength, so their owners are still let in.
+const maxSignInPasswordLength = 1024
+
 type signInput struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
@@ -44,6 +50,9 @@ func (s *signInput) UnmarshalJSON(data []byte) error {
 	}
 	if result.Login == nil || result.Password == nil {
 		return errors.New("missing required fields")
+	} else if len(*result.Password) > maxSignInPasswordLength {
+		return filmoteka.ValidationError("password is too long",
+			map[string]string{"password": fmt.Sprintf("expected at most %d bytes", maxSignInPasswordLength)})
 	} else {
 		s.Login = *result.Login
 		s.Password = *result.Password
@@ -54,23 +63,111 @@ func (s *signInput) UnmarshalJSON(data []byte) error {
 func authUser(r *Router, writer http.ResponseWriter, request *http.Request) {
 	var input signInput
 	if err := parseBody(request.Body, &input); err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	token, err := r.service.User.GenerateToken(input.Login, input.Password)
+	tokens, err := r.service.User.GenerateToken(input.Login, input.Password)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusUnauthorized, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	if err := writeBody(writer, token); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+	if err := writeBody(writer, tokens); err != nil {
+		r.sendError(writer, err)
 		return
 	}
 	logrus.Infof("the %s user logged in", input.Login)
 }
 
+type refreshInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (i *refreshInput) UnmarshalJSON(data []byte) error {
+	result := struct {
+		RefreshToken *string `json:"refresh_token"`
+	}{}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	if result.RefreshToken == nil || *result.RefreshToken == "" {
+		return errors.New("missing required fields")
+	}
+	i.RefreshToken = *result.RefreshToken
+	return nil
+}
+
+func refreshToken(r *Router, writer http.ResponseWriter, request *http.Request) {
+	var input refreshInput
+	if err := parseBody(request.Body, &input); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	tokens, err := r.service.User.RefreshToken(input.RefreshToken)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, tokens); err != nil {
+		r.sendError(writer, err)
+	}
+}
+
+func logout(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	token, err := getToken(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	// the refresh token is optional, without it only the access token is revoked
+	var input refreshInput
+	if err := parseBody(request.Body, &input); err != nil && !errors.Is(err, io.EOF) {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := r.service.User.Logout(token, input.RefreshToken); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully logged out"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("user with id %d logged out", id)
+}
+
+func logoutEverywhere(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := r.service.User.LogoutEverywhere(id); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully logged out from all sessions"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("all sessions of user with id %d have been closed", id)
+}
+
 type updateInput struct {
 	Login    string `json:"login"`
 	UserRole string `json:"user_role"`
@@ -85,8 +182,9 @@ func (u *updateInput) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &result); err != nil {
 		return err
 	}
-	if result.Login == nil || result.UserRole == nil || (*result.UserRole != "regular" &&
-		*result.UserRole != "admin") {
+	if result.Login == nil || result.UserRole == nil {
+		return errors.New("invalid state for required filed(s)")
+	} else if _, ok := filmoteka.UserRoles[*result.UserRole]; !ok {
 		return errors.New("invalid state for required filed(s)")
 	} else {
 		u.Login = *result.Login
@@ -98,24 +196,30 @@ func (u *updateInput) UnmarshalJSON(data []byte) error {
 func updateUser(r *Router, writer http.ResponseWriter, request *http.Request) {
 	id, err := getUserId(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
+		return
+	}
+
+	role, err := getUserRole(request)
+	if err != nil {
+		r.sendError(writer, err)
 		return
 	}
 
 	var update updateInput
 	if err := parseBody(request.Body, &update); err != nil {
-		r.sendErrorResponse(writer, http.StatusBadRequest, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
-	err = r.service.User.UpdateUser(update.Login, update.UserRole)
+	err = r.service.User.UpdateUser(id, role, update.Login, update.UserRole)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	if err = writeBody(writer, "successfully update user role"); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 	}
 
 	logrus.Infof("the role of the %s user has been changed to %s by a user with an id %d",
@@ -125,17 +229,24 @@ func updateUser(r *Router, writer http.ResponseWriter, request *http.Request) {
 func deleteUser(r *Router, writer http.ResponseWriter, request *http.Request) {
 	id, err := getUserId(request)
 	if err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
+		return
 	}
 
 	if err := r.service.User.DeleteUserById(id); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 		return
 	}
 
 	if err = writeBody(writer, "user successfully deleted"); err != nil {
-		r.sendErrorResponse(writer, http.StatusInternalServerError, err.Error())
+		r.sendError(writer, err)
 	}
 
 	logrus.Infof("user with id %d has been deleted", id)
 }
+
+func getJWKS(r *Router, writer http.ResponseWriter, request *http.Request) {
+	if err := writeBody(writer, r.service.User.GetJWKS()); err != nil {
+		r.sendError(writer, err)
+	}
+}
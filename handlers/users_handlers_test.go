@@ -46,15 +46,43 @@ func TestHandler_createNewUser(t *testing.T) {
 			inputUser:            filmoteka.User{},
 			mockBehavior:         func(r *mock_service.MockUser, user filmoteka.User) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `invalid state for required field(s)`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required field(s)"}`,
 		},
 		{
-			name:                 "Wrong role",
-			inputBody:            `{"login": "login", "password": "qwerty", "user_role": "smt"}`,
+			name:                 "Too Long Password",
+			inputBody:            fmt.Sprintf(`{"login": "login", "password": "%s"}`, strings.Repeat("p", 73)),
 			inputUser:            filmoteka.User{},
 			mockBehavior:         func(r *mock_service.MockUser, user filmoteka.User) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `invalid state for required field(s)`,
+			expectedResponseBody: `{"code":"validation_error","message":"password is too long","details":{"password":"expected at most 72 bytes"}}`,
+		},
+		{
+			name:      "Admin Role Ignored",
+			inputBody: `{"login": "login", "password": "qwerty", "user_role": "admin"}`,
+			inputUser: filmoteka.User{
+				Login:    "login",
+				Password: "qwerty",
+				UserRole: filmoteka.RegularRole,
+			},
+			mockBehavior: func(r *mock_service.MockUser, user filmoteka.User) {
+				r.EXPECT().CreateUser(user).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successful"`,
+		},
+		{
+			name:      "No Role",
+			inputBody: `{"login": "login", "password": "qwerty"}`,
+			inputUser: filmoteka.User{
+				Login:    "login",
+				Password: "qwerty",
+				UserRole: filmoteka.RegularRole,
+			},
+			mockBehavior: func(r *mock_service.MockUser, user filmoteka.User) {
+				r.EXPECT().CreateUser(user).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successful"`,
 		},
 		{
 			name:      "Service Error",
@@ -68,7 +96,7 @@ func TestHandler_createNewUser(t *testing.T) {
 				r.EXPECT().CreateUser(user).Return(0, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -104,7 +132,7 @@ func TestHandler_authUser(t *testing.T) {
 	// Init Test Table
 	type mockBehavior func(r *mock_service.MockUser, input signInput)
 
-	const token = "fmekwfmw"
+	tokens := filmoteka.TokenPair{AccessToken: "fmekwfmw", RefreshToken: "dmwkqlmd", ExpiresIn: 900}
 
 	tests := []struct {
 		name                 string
@@ -122,10 +150,10 @@ func TestHandler_authUser(t *testing.T) {
 				Password: "qwerty",
 			},
 			mockBehavior: func(r *mock_service.MockUser, input signInput) {
-				r.EXPECT().GenerateToken(input.Login, input.Password).Return(token, nil)
+				r.EXPECT().GenerateToken(input.Login, input.Password).Return(tokens, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: fmt.Sprintf(`"%s"`, token),
+			expectedResponseBody: `{"access_token":"fmekwfmw","refresh_token":"dmwkqlmd","expires_in":900}`,
 		},
 		{
 			name:                 "Wrong Input",
@@ -133,7 +161,7 @@ func TestHandler_authUser(t *testing.T) {
 			inputUser:            signInput{},
 			mockBehavior:         func(r *mock_service.MockUser, input signInput) {},
 			expectedStatusCode:   400,
-			expectedResponseBody: `missing required fields`,
+			expectedResponseBody: `{"code":"validation_error","message":"missing required fields"}`,
 		},
 		{
 			name:      "Service Error",
@@ -143,10 +171,10 @@ func TestHandler_authUser(t *testing.T) {
 				Password: "qwerty",
 			},
 			mockBehavior: func(r *mock_service.MockUser, input signInput) {
-				r.EXPECT().GenerateToken(input.Login, input.Password).Return("", errors.New("something went wrong"))
+				r.EXPECT().GenerateToken(input.Login, input.Password).Return(filmoteka.TokenPair{}, filmoteka.UnauthorizedError("something went wrong"))
 			},
 			expectedStatusCode:   401,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"unauthorized","message":"something went wrong"}`,
 		},
 	}
 
@@ -178,6 +206,240 @@ func TestHandler_authUser(t *testing.T) {
 	}
 }
 
+func TestHandler_refreshToken(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockUser)
+
+	tokens := filmoteka.TokenPair{AccessToken: "vmekwlqm", RefreshToken: "qpwmvkeo", ExpiresIn: 900}
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"refresh_token": "dmwkqlmd"}`,
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().RefreshToken("dmwkqlmd").Return(tokens, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"access_token":"vmekwlqm","refresh_token":"qpwmvkeo","expires_in":900}`,
+		},
+		{
+			name:                 "Wrong Input",
+			inputBody:            `{"refresh_token": ""}`,
+			mockBehavior:         func(r *mock_service.MockUser) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"missing required fields"}`,
+		},
+		{
+			name:      "Revoked token",
+			inputBody: `{"refresh_token": "dmwkqlmd"}`,
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().RefreshToken("dmwkqlmd").Return(filmoteka.TokenPair{}, filmoteka.UnauthorizedError("invalid refresh token"))
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"code":"unauthorized","message":"invalid refresh token"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockUser(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{User: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("POST", "/users/refresh", refreshToken)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/users/refresh",
+				bytes.NewBufferString(test.inputBody))
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestHandler_logout(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockUser)
+
+	const token = "fmekwfmw"
+
+	tests := []struct {
+		name                 string
+		headerName           string
+		headerValue          string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Ok",
+			headerName:  authorizationHeader,
+			headerValue: fmt.Sprintf("Bearer %s", token),
+			inputBody:   `{"refresh_token": "dmwkqlmd"}`,
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().ParseToken(token).Return(1, nil)
+				r.EXPECT().Logout(token, "dmwkqlmd").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully logged out"`,
+		},
+		{
+			name:        "Ok without refresh token",
+			headerName:  authorizationHeader,
+			headerValue: fmt.Sprintf("Bearer %s", token),
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().ParseToken(token).Return(1, nil)
+				r.EXPECT().Logout(token, "").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully logged out"`,
+		},
+		{
+			name:        "Revoked token",
+			headerName:  authorizationHeader,
+			headerValue: fmt.Sprintf("Bearer %s", token),
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().ParseToken(token).Return(0, filmoteka.UnauthorizedError("token has been revoked"))
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"code":"unauthorized","message":"token has been revoked"}`,
+		},
+		{
+			name:        "Service Error",
+			headerName:  authorizationHeader,
+			headerValue: fmt.Sprintf("Bearer %s", token),
+			inputBody:   `{"refresh_token": "dmwkqlmd"}`,
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().ParseToken(token).Return(1, nil)
+				r.EXPECT().Logout(token, "dmwkqlmd").Return(errors.New("invalid refresh token"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockUser(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{User: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("POST", "/users/logout", logout)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/users/logout",
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set(test.headerName, test.headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestHandler_logoutEverywhere(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockUser)
+
+	const token = "fmekwfmw"
+
+	tests := []struct {
+		name                 string
+		headerName           string
+		headerValue          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Ok",
+			headerName:  authorizationHeader,
+			headerValue: fmt.Sprintf("Bearer %s", token),
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().ParseToken(token).Return(1, nil)
+				r.EXPECT().LogoutEverywhere(1).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully logged out from all sessions"`,
+		},
+		{
+			name:                 "Empty header",
+			mockBehavior:         func(r *mock_service.MockUser) {},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"code":"unauthorized","message":"empty header"}`,
+		},
+		{
+			name:        "Service Error",
+			headerName:  authorizationHeader,
+			headerValue: fmt.Sprintf("Bearer %s", token),
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().ParseToken(token).Return(1, nil)
+				r.EXPECT().LogoutEverywhere(1).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockUser(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{User: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("POST", "/users/logout/all", logoutEverywhere)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/users/logout/all",
+				bytes.NewBufferString(""))
+			if test.headerName != "" {
+				req.Header.Set(test.headerName, test.headerValue)
+			}
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
 func TestHandler_updateUser(t *testing.T) {
 	// Init Test Table
 	type mockBehavior func(r *mock_service.MockUser, input updateInput)
@@ -205,8 +467,8 @@ func TestHandler_updateUser(t *testing.T) {
 			},
 			mockBehavior: func(r *mock_service.MockUser, input updateInput) {
 				r.EXPECT().ParseToken(token).Return(1, nil)
-				r.EXPECT().ValidateUser(1).Return(true, nil)
-				r.EXPECT().UpdateUser(input.Login, input.UserRole).Return(nil)
+				r.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r.EXPECT().UpdateUser(1, filmoteka.AdminRole, input.Login, input.UserRole).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `"successfully update user role"`,
@@ -222,10 +484,10 @@ func TestHandler_updateUser(t *testing.T) {
 			},
 			mockBehavior: func(r *mock_service.MockUser, input updateInput) {
 				r.EXPECT().ParseToken(token).Return(1, nil)
-				r.EXPECT().ValidateUser(1).Return(true, nil)
+				r.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
 			},
 			expectedStatusCode:   400,
-			expectedResponseBody: `invalid state for required filed(s)`,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required filed(s)"}`,
 		},
 		{
 			name:        "Locked",
@@ -235,10 +497,10 @@ func TestHandler_updateUser(t *testing.T) {
 			inputUser:   updateInput{},
 			mockBehavior: func(r *mock_service.MockUser, input updateInput) {
 				r.EXPECT().ParseToken(token).Return(1, nil)
-				r.EXPECT().ValidateUser(1).Return(false, nil)
+				r.EXPECT().GetUserRole(1).Return(filmoteka.RegularRole, nil)
 			},
-			expectedStatusCode:   423,
-			expectedResponseBody: `this function locked for current user`,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"forbidden","message":"this function locked for current user"}`,
 		},
 		{
 			name:        "Service Error",
@@ -251,11 +513,11 @@ func TestHandler_updateUser(t *testing.T) {
 			},
 			mockBehavior: func(r *mock_service.MockUser, input updateInput) {
 				r.EXPECT().ParseToken(token).Return(1, nil)
-				r.EXPECT().ValidateUser(1).Return(true, nil)
-				r.EXPECT().UpdateUser(input.Login, input.UserRole).Return(errors.New("something went wrong"))
+				r.EXPECT().GetUserRole(1).Return(filmoteka.AdminRole, nil)
+				r.EXPECT().UpdateUser(1, filmoteka.AdminRole, input.Login, input.UserRole).Return(errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -318,10 +580,10 @@ func TestHandler_deleteUser(t *testing.T) {
 			headerName:  authorizationHeader,
 			headerValue: fmt.Sprintf("Bearer %s", token),
 			mockBehavior: func(r *mock_service.MockUser) {
-				r.EXPECT().ParseToken(token).Return(0, errors.New("invalid token"))
+				r.EXPECT().ParseToken(token).Return(0, filmoteka.UnauthorizedError("invalid token"))
 			},
 			expectedStatusCode:   401,
-			expectedResponseBody: `invalid token`,
+			expectedResponseBody: `{"code":"unauthorized","message":"invalid token"}`,
 		},
 		{
 			name:        "Service Error",
@@ -332,7 +594,7 @@ func TestHandler_deleteUser(t *testing.T) {
 				r.EXPECT().DeleteUserById(1).Return(errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
-			expectedResponseBody: `something went wrong`,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
 		},
 	}
 
@@ -364,3 +626,61 @@ func TestHandler_deleteUser(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_getJWKS(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockUser)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().GetJWKS().Return(filmoteka.JWKS{Keys: []filmoteka.JWK{
+					{Kty: "OKP", Kid: "2024-ed", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg"},
+				}})
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"keys":[{"kty":"OKP","kid":"2024-ed","use":"sig","alg":"EdDSA","crv":"Ed25519",` +
+				`"x":"11qYAYKxCrfVS_7TyWQHOg"}]}`,
+		},
+		{
+			name: "Only HMAC keys",
+			mockBehavior: func(r *mock_service.MockUser) {
+				r.EXPECT().GetJWKS().Return(filmoteka.JWKS{Keys: []filmoteka.JWK{}})
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"keys":[]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockUser(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{User: repo}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/.well-known/jwks.json", getJWKS)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
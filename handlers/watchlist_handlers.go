@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"github.com/jorgini/filmoteka"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+func addToWatchlist(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	var entry filmoteka.WatchlistEntry
+	if err := parseBody(request.Body, &entry); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	entry.UserId = id
+
+	if err = r.service.Watchlist.AddToWatchlist(entry); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully add to watchlist"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("film with id %d was marked as %s by user with id %d", entry.FilmId, entry.Status, id)
+}
+
+func getWatchlist(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	status := request.URL.Query().Get("status")
+	if status == "" {
+		status = filmoteka.WantToWatch
+	} else if status != filmoteka.WantToWatch && status != filmoteka.Watched {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "invalid status of watchlist")
+		return
+	}
+
+	page, size, err := r.getPageParams(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	items, total, err := r.service.Watchlist.GetWatchlist(id, status, page, size)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err := writeBody(writer, newPage(request, items, page, size, total)); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+	logrus.Infof("%s list of user with id %d was sent", status, id)
+}
+
+func removeFromWatchlist(r *Router, writer http.ResponseWriter, request *http.Request) {
+	id, err := getUserId(request)
+	if err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	filmId, err := getIntParam(request, "film_id")
+	if err != nil {
+		r.sendErrorResponse(writer, http.StatusBadRequest, "film id doesnt specified to remove from watchlist")
+		return
+	}
+
+	if err = r.service.Watchlist.RemoveFromWatchlist(id, filmId); err != nil {
+		r.sendError(writer, err)
+		return
+	}
+
+	if err = writeBody(writer, "successfully remove from watchlist"); err != nil {
+		r.sendError(writer, err)
+	}
+
+	logrus.Infof("film with id %d was removed from watchlist by user with id %d", filmId, id)
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/service"
+	"github.com/jorgini/filmoteka/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter_addToWatchlist(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser, entry filmoteka.WatchlistEntry)
+
+	var (
+		date        = time.Time{}.AddDate(2022, 7, 10)
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+	)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		inputEntry           filmoteka.WatchlistEntry
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "Ok want",
+			inputBody:  `{"film_id": 1, "status": "want"}`,
+			inputEntry: filmoteka.WatchlistEntry{UserId: userId, FilmId: 1, Status: "want"},
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser, entry filmoteka.WatchlistEntry) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().AddToWatchlist(entry).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully add to watchlist"`,
+		},
+		{
+			name:      "Ok watched",
+			inputBody: `{"film_id": 1, "status": "watched", "watched_on": "11-08-2023"}`,
+			inputEntry: filmoteka.WatchlistEntry{UserId: userId, FilmId: 1, Status: "watched",
+				WatchedOn: (*filmoteka.Date)(&date)},
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser, entry filmoteka.WatchlistEntry) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().AddToWatchlist(entry).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully add to watchlist"`,
+		},
+		{
+			name:       "Wrong Input",
+			inputBody:  `{"film_id": 1, "status": "want", "watched_on": "11-08-2023"}`,
+			inputEntry: filmoteka.WatchlistEntry{},
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser, entry filmoteka.WatchlistEntry) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid state for required field(s)"}`,
+		},
+		{
+			name:       "Service Error",
+			inputBody:  `{"film_id": 1, "status": "want"}`,
+			inputEntry: filmoteka.WatchlistEntry{UserId: userId, FilmId: 1, Status: "want"},
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser, entry filmoteka.WatchlistEntry) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().AddToWatchlist(entry).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockWatchlist(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2, test.inputEntry)
+
+			services := &service.Service{Watchlist: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("POST", "/watchlist", addToWatchlist)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/watchlist",
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_getWatchlist(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser)
+
+	var (
+		date        = time.Time{}.AddDate(2022, 7, 10)
+		addedAt     = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+		limit       = 10
+		items       = []filmoteka.WatchlistItem{{
+			Film: filmoteka.Film{
+				Id:        1,
+				Title:     "test",
+				IssueDate: (*filmoteka.Date)(&date),
+				Rating:    5,
+			},
+			Status:    "watched",
+			WatchedOn: (*filmoteka.Date)(&date),
+			AddedAt:   addedAt,
+		}}
+	)
+
+	tests := []struct {
+		name                 string
+		headerValue          string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Ok",
+			headerValue: headerValue,
+			params:      "status=watched&page=1",
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().GetWatchlist(userId, "watched", 1, limit).Return(items, 1, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"items":[{"id":1,"title":"test","description":"","issue_date":"11-08-2023","rating":5,` +
+				`"avg_rating":0,"votes":0,"status":"watched","watched_on":"11-08-2023","added_at":"2024-03-01T12:00:00Z"}],` +
+				`"page":1,"page_size":10,"total":1}`,
+		},
+		{
+			name:        "Default Page",
+			headerValue: headerValue,
+			params:      "",
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().GetWatchlist(userId, "want", 1, limit).Return(nil, 0, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":1,"page_size":10,"total":0}`,
+		},
+		{
+			name:                 "Unauthorized",
+			headerValue:          "",
+			params:               "page=1",
+			mockBehavior:         func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"code":"unauthorized","message":"empty header"}`,
+		},
+		{
+			name:        "Wrong Status",
+			headerValue: headerValue,
+			params:      "status=seen&page=1",
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid status of watchlist"}`,
+		},
+		{
+			name:        "Over page",
+			headerValue: headerValue,
+			params:      "page=2",
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().GetWatchlist(userId, "want", 2, limit).Return([]filmoteka.WatchlistItem{}, 1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"items":[],"page":2,"page_size":10,"total":1,"prev":"/watchlist?page=1\u0026page_size=10"}`,
+		},
+		{
+			name:        "Wrong Page",
+			headerValue: headerValue,
+			params:      "page=first",
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"invalid page number","details":{"page":"expected int"}}`,
+		},
+		{
+			name:        "Service Error",
+			headerValue: headerValue,
+			params:      "page=1",
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().GetWatchlist(userId, "want", 1, limit).Return(nil, 0, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockWatchlist(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2)
+
+			services := &service.Service{Watchlist: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("GET", "/watchlist", getWatchlist)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/watchlist?"+test.params, bytes.NewBufferString(""))
+			if test.headerValue != "" {
+				req.Header.Set(headerName, test.headerValue)
+			}
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
+
+func TestRouter_removeFromWatchlist(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser)
+
+	var (
+		headerName  = "Authorization"
+		headerValue = "Bearer test"
+		token       = "test"
+		userId      = 1
+		filmId      = 1
+	)
+
+	tests := []struct {
+		name                 string
+		params               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			params: "film_id=1",
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().RemoveFromWatchlist(userId, filmId).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `"successfully remove from watchlist"`,
+		},
+		{
+			name:   "Wrong Params",
+			params: "film_id=fksfm",
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"code":"validation_error","message":"film id doesnt specified to remove from watchlist"}`,
+		},
+		{
+			name:   "Service Error",
+			params: "film_id=1",
+			mockBehavior: func(r1 *mock_service.MockWatchlist, r2 *mock_service.MockUser) {
+				r2.EXPECT().ParseToken(token).Return(userId, nil)
+				r1.EXPECT().RemoveFromWatchlist(userId, filmId).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo1 := mock_service.NewMockWatchlist(c)
+			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo1, repo2)
+
+			services := &service.Service{Watchlist: repo1, User: repo2}
+			handler := Router{service: services}
+			handler.AddEndPoint("DELETE", "/watchlist", removeFromWatchlist)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/watchlist?"+test.params,
+				bytes.NewBufferString(""))
+			req.Header.Set(headerName, headerValue)
+
+			// Make Request
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedResponseBody, strings.ReplaceAll(w.Body.String(), "\n", ""))
+		})
+	}
+}
@@ -3,29 +3,38 @@ package filmoteka
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"strings"
 )
 
 type InputActor struct {
-	Name    string `json:"name"`
-	Surname string `json:"surname"`
+	Name      string `json:"name"`
+	Surname   string `json:"surname"`
+	Character string `json:"character,omitempty" db:"character_name"`
+	Billing   int    `json:"billing,omitempty" db:"billing_order"`
 }
 
 func (a *InputActor) UnmarshalJSON(data []byte) error {
 	result := struct {
-		Name    *string `json:"name"`
-		Surname *string `json:"surname"`
+		Name      *string `json:"name"`
+		Surname   *string `json:"surname"`
+		Character *string `json:"character"`
+		Billing   *int    `json:"billing"`
 	}{}
 
 	if err := json.Unmarshal(data, &result); err != nil {
 		return err
 	}
-	if result.Name == nil || result.Surname == nil {
+	if result.Name == nil || result.Surname == nil || (result.Billing != nil && *result.Billing < 1) {
 		return errors.New("invalid state for required filed(s)")
 	} else {
 		a.Name = *result.Name
 		a.Surname = *result.Surname
+		if result.Character != nil {
+			a.Character = *result.Character
+		}
+		if result.Billing != nil {
+			a.Billing = *result.Billing
+		}
 	}
 	return nil
 }
@@ -56,12 +65,16 @@ func (c *Cast) UnmarshalJSON(data []byte) error {
 type InputFilm struct {
 	Film
 	Cast
+	Genres  []string `json:"genres,omitempty"`
+	Credits Credits  `json:"credits,omitempty"`
 }
 
 func (i *InputFilm) UnmarshalJSON(data []byte) error {
 	var f Film
 	actors := struct {
-		Cast Cast `json:"Cast"`
+		Cast    Cast     `json:"Cast"`
+		Genres  []string `json:"genres"`
+		Credits Credits  `json:"credits"`
 	}{}
 	if err := f.UnmarshalJSON(data); err != nil {
 		return err
@@ -71,17 +84,23 @@ func (i *InputFilm) UnmarshalJSON(data []byte) error {
 	}
 	i.Film = f
 	i.Cast = actors.Cast
+	i.Genres = actors.Genres
+	i.Credits = actors.Credits
 	return nil
 }
 
 type ActorListItem struct {
-	Actor Actor
-	Films []Film
+	Actor   Actor
+	Films   []Film
+	Credits FilmCredits `json:",omitempty"`
 }
 
+// ActorSearchFragment selects actors by substrings of their names or, with Fuzzy, by the
+// trigram similarity of the names, which tolerates typos.
 type ActorSearchFragment struct {
 	Name    *string `json:"name"`
 	Surname *string `json:"surname"`
+	Fuzzy   bool    `json:"fuzzy"`
 }
 
 func (a *ActorSearchFragment) UnmarshalJSON(data []byte) error {
@@ -95,14 +114,21 @@ func (a *ActorSearchFragment) UnmarshalJSON(data []byte) error {
 	} else {
 		a.Name = result.Name
 		a.Surname = result.Surname
+		a.Fuzzy = result.Fuzzy
 	}
 	return nil
 }
 
+// FilmSearchFragment selects films by substrings of the title or the names of the cast,
+// or, when Query is set, by full-text search over titles and descriptions. Fuzzy matches
+// the names of the cast by trigram similarity instead of substrings.
 type FilmSearchFragment struct {
 	Title   *string `json:"title"`
 	Name    *string `json:"name"`
 	Surname *string `json:"surname"`
+	Genre   *string `json:"genre"`
+	Query   *string `json:"query"`
+	Fuzzy   bool    `json:"fuzzy"`
 }
 
 func (a *FilmSearchFragment) UnmarshalJSON(data []byte) error {
@@ -111,23 +137,35 @@ func (a *FilmSearchFragment) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &result); err != nil {
 		return err
 	}
-	if result.Name == nil && result.Surname == nil && result.Title == nil {
+	if result.Name == nil && result.Surname == nil && result.Title == nil && result.Genre == nil &&
+		result.Query == nil {
 		return errors.New("parameters for search not specified")
+	} else if result.Query != nil && (result.Name != nil || result.Surname != nil || result.Title != nil) {
+		return errors.New("full-text query can not be combined with title, name or surname")
+	} else if result.Query != nil && strings.TrimSpace(*result.Query) == "" {
+		return errors.New("full-text query is empty")
+	} else if result.Fuzzy && result.Name == nil && result.Surname == nil {
+		return errors.New("fuzzy search needs name or surname")
 	} else {
 		a.Title = result.Title
 		a.Name = result.Name
 		a.Surname = result.Surname
+		a.Genre = result.Genre
+		a.Query = result.Query
+		a.Fuzzy = result.Fuzzy
 	}
 	return nil
 }
 
 type UpdateFilmInput struct {
-	Id          *int    `json:"id"`
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	IssueDate   *Date   `json:"issue_date"`
-	Rating      *int    `json:"int"`
-	Actors      *Cast   `json:"cast"`
+	Id          *int      `json:"id"`
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	IssueDate   *Date     `json:"issue_date"`
+	Rating      *int      `json:"rating"`
+	Actors      *Cast     `json:"cast"`
+	Genres      *[]string `json:"genres"`
+	Credits     *Credits  `json:"credits"`
 }
 
 func (u *UpdateFilmInput) UnmarshalJSON(data []byte) error {
@@ -137,7 +175,8 @@ func (u *UpdateFilmInput) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	if result.Title == nil && result.Description == nil && result.IssueDate == nil &&
-		result.Rating == nil && result.Actors == nil {
+		result.Rating == nil && result.Actors == nil && result.Genres == nil &&
+		result.Credits == nil {
 		return errors.New("invalid state for required filed to update film")
 	} else {
 		u.Title = result.Title
@@ -145,42 +184,28 @@ func (u *UpdateFilmInput) UnmarshalJSON(data []byte) error {
 		u.IssueDate = result.IssueDate
 		u.Rating = result.Rating
 		u.Actors = result.Actors
+		u.Genres = result.Genres
+		u.Credits = result.Credits
 	}
 	return nil
 }
 
-func (u *UpdateFilmInput) GetValuesUpdate() string {
-	values := make([]string, 0, 4)
-	if u.Title != nil {
-		values = append(values, "title=$1")
-	}
-	if u.Description != nil {
-		values = append(values, fmt.Sprintf("description=$%d", len(values)+1))
-	}
-	if u.IssueDate != nil {
-		values = append(values, fmt.Sprintf("issue_date=$%d", len(values)+1))
-	}
-	if u.Rating != nil {
-		values = append(values, fmt.Sprintf("rating=$%d", len(values)+1))
-	}
-	return strings.Join(values, ",\n")
-}
-
-func (u *UpdateFilmInput) GetArgsUpdate() []interface{} {
-	args := make([]interface{}, 0, 4)
+// UpdateValues returns the new values of the changed columns of the film.
+func (u *UpdateFilmInput) UpdateValues() map[string]interface{} {
+	values := make(map[string]interface{}, 4)
 	if u.Title != nil {
-		args = append(args, *u.Title)
+		values["title"] = *u.Title
 	}
 	if u.Description != nil {
-		args = append(args, *u.Description)
+		values["description"] = *u.Description
 	}
 	if u.IssueDate != nil {
-		args = append(args, u.IssueDate.String())
+		values["issue_date"] = u.IssueDate
 	}
 	if u.Rating != nil {
-		args = append(args, *u.Rating)
+		values["rating"] = *u.Rating
 	}
-	return args
+	return values
 }
 
 type UpdateActorInput struct {
@@ -208,36 +233,20 @@ func (u *UpdateActorInput) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (u *UpdateActorInput) GetValuesUpdate() string {
-	values := make([]string, 0, 4)
-	if u.Name != nil {
-		values = append(values, fmt.Sprintf("name=$%d", len(values)+1))
-	}
-	if u.Surname != nil {
-		values = append(values, fmt.Sprintf("surname=$%d", len(values)+1))
-	}
-	if u.Sex != nil {
-		values = append(values, fmt.Sprintf("sex=$%d", len(values)+1))
-	}
-	if u.Birthday != nil {
-		values = append(values, fmt.Sprintf("birthday=$%d", len(values)+1))
-	}
-	return strings.Join(values, ",\n")
-}
-
-func (u *UpdateActorInput) GetArgsUpdate() []interface{} {
-	args := make([]interface{}, 0, 4)
+// UpdateValues returns the new values of the changed columns of the actor.
+func (u *UpdateActorInput) UpdateValues() map[string]interface{} {
+	values := make(map[string]interface{}, 4)
 	if u.Name != nil {
-		args = append(args, *u.Name)
+		values["name"] = *u.Name
 	}
 	if u.Surname != nil {
-		args = append(args, *u.Surname)
+		values["surname"] = *u.Surname
 	}
 	if u.Sex != nil {
-		args = append(args, *u.Sex)
+		values["sex"] = *u.Sex
 	}
 	if u.Birthday != nil {
-		args = append(args, u.Birthday.String())
+		values["birthday"] = u.Birthday
 	}
-	return args
+	return values
 }
@@ -5,7 +5,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/jorgini/filmoteka"
 	"github.com/jorgini/filmoteka/configs"
-	"strings"
+	"github.com/lib/pq"
 )
 
 type ActorDao struct {
@@ -18,15 +18,36 @@ func NewActorDao(db *sqlx.DB) *ActorDao {
 	}
 }
 
-func getSearchCondition(name, surname *string) string {
-	condition := make([]string, 0, 2)
-	if name != nil {
-		condition = append(condition, fmt.Sprintf("POSITION('%s' in name)>0", *name))
+// actorFields are the columns of filmoteka.Actor, the actors table keeps also the
+// normalised names for search.
+const actorFields = "a.id, a.name, a.surname, a.sex, a.birthday"
+
+// getActorSearchQuery matches the names by substrings of their normalised forms or, for
+// fuzzy search, by trigram similarity.
+func getActorSearchQuery(fragment filmoteka.ActorSearchFragment) *query {
+	q := newQuery(actorColumns).where(liveActor)
+	if fragment.Fuzzy {
+		return q.whereSimilar(fuzzyName(fragment.Name, fragment.Surname))
+	}
+
+	if fragment.Name != nil {
+		q.whereNormalized("name_norm", *fragment.Name)
 	}
-	if surname != nil {
-		condition = append(condition, fmt.Sprintf("POSITION('%s' in surname)>0", *surname))
+	if fragment.Surname != nil {
+		q.whereNormalized("surname_norm", *fragment.Surname)
 	}
-	return strings.Join(condition, " AND ")
+	return q
+}
+
+// getActorSortColumns adds the number of live films of the actor to the columns an actor
+// list may be sorted by.
+func getActorSortColumns() columns {
+	cols := columns{"film_count": fmt.Sprintf("(SELECT COUNT(*) FROM %s s INNER JOIN %s f ON s.film_id=f.id WHERE s.actor_id=a.id AND %s)",
+		configs.EnvStarredTable(), configs.EnvFilmTable(), liveFilm)}
+	for name, expr := range actorColumns {
+		cols[name] = expr
+	}
+	return cols
 }
 
 func (a *ActorDao) CreateActor(tx *sqlx.Tx, actor filmoteka.Actor) (int, error) {
@@ -36,80 +57,211 @@ func (a *ActorDao) CreateActor(tx *sqlx.Tx, actor filmoteka.Actor) (int, error)
 	var id int
 	row := tx.QueryRow(query, actor.Name, actor.Surname, actor.Sex, actor.Birthday.String())
 	if err := row.Scan(&id); err != nil {
-		return 0, err
+		return 0, dbError(err)
 	}
 	return id, nil
 }
 
-func (a *ActorDao) UpdateActor(tx *sqlx.Tx, actor filmoteka.UpdateActorInput) error {
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=%d", configs.EnvActorTable(), actor.GetValuesUpdate(), *actor.Id)
+// lockLiveActor fails with not found for an actor missing or in the trash and keeps the
+// actor out of the trash until the end of the transaction.
+func lockLiveActor(tx *sqlx.Tx, id int, lock string) error {
+	query := fmt.Sprintf("SELECT a.id FROM %s a WHERE a.id=$1 AND %s %s", configs.EnvActorTable(), liveActor, lock)
 
-	if _, err := tx.Query(query, actor.GetArgsUpdate()...); err != nil {
+	var ids []int
+	if err := tx.Select(&ids, query, id); err != nil {
+		return dbError(err)
+	} else if len(ids) == 0 {
+		return filmoteka.NotFoundError("actor not found")
+	}
+	return nil
+}
+
+// LockActor fails with not found for an actor missing or in the trash, otherwise nobody
+// else can change or delete the actor until the end of the transaction.
+func (a *ActorDao) LockActor(tx *sqlx.Tx, id int) error {
+	return lockLiveActor(tx, id, updateLock)
+}
+
+func (a *ActorDao) UpdateActor(tx *sqlx.Tx, actor filmoteka.UpdateActorInput) error {
+	q := newQuery(actorColumns).setAll(actor.UpdateValues())
+	query, args, err := q.build("UPDATE %s SET %s WHERE id=%s AND deleted_at IS NULL", configs.EnvActorTable(),
+		q.setClause(), q.bind(*actor.Id))
+	if err != nil {
 		return err
 	}
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.NotFoundError("actor not found")
+	}
 	return nil
 }
 
 func (a *ActorDao) GetActorId(name, surname string) (int, error) {
-	query := fmt.Sprintf("SELECT id FROM %s WHERE name=$1 AND surname=$2", configs.EnvActorTable())
+	query := fmt.Sprintf("SELECT id FROM %s WHERE name=$1 AND surname=$2 AND deleted_at IS NULL", configs.EnvActorTable())
 
 	var id int
 	row := a.db.QueryRow(query, name, surname)
 	if err := row.Scan(&id); err != nil {
-		return 0, err
+		return 0, dbError(err)
 	}
 	return id, nil
 }
 
 func (a *ActorDao) GetActorById(id int) (filmoteka.Actor, error) {
-	query := fmt.Sprintf("SELECT * FROM %s WHERE id=$1", configs.EnvActorTable())
+	query := fmt.Sprintf("SELECT %s FROM %s a WHERE a.id=$1 AND %s", actorFields, configs.EnvActorTable(), liveActor)
 
 	var actor filmoteka.Actor
 	err := a.db.Get(&actor, query, id)
 	if err != nil {
-		return filmoteka.Actor{}, err
+		return filmoteka.Actor{}, dbError(err)
 	}
 	return actor, nil
 }
 
-func (a *ActorDao) GetActorsList(page, limit int) ([]filmoteka.Actor, error) {
-	query := fmt.Sprintf("SELECT * FROM %s LIMIT $1 OFFSET $2", configs.EnvActorTable())
+func (a *ActorDao) GetActorsList(sort []filmoteka.SortKey, page, limit int) ([]filmoteka.Actor, error) {
+	q := newQuery(getActorSortColumns()).where(liveActor).orderByKeys(sort)
+	query, args, err := q.build("SELECT %s FROM %s a %s %s %s", actorFields, configs.EnvActorTable(), q.whereClause(),
+		q.orderClause(), q.page(page, limit))
+	if err != nil {
+		return nil, err
+	}
 
 	var actors []filmoteka.Actor
-	if err := a.db.Select(&actors, query, limit, (page-1)*limit); err != nil {
-		return nil, err
+	if err := a.db.Select(&actors, query, args...); err != nil {
+		return nil, dbError(err)
 	}
 
 	return actors, nil
 }
 
-func (a *ActorDao) SearchActor(page, limit int, name, surname *string) ([]filmoteka.Actor, error) {
-	query := fmt.Sprintf("SELECT * FROM %s WHERE %s LIMIT %d OFFSET %d",
-		configs.EnvActorTable(), getSearchCondition(name, surname), limit, limit*(page-1))
+func (a *ActorDao) CountActors() (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE deleted_at IS NULL", configs.EnvActorTable())
 
-	var actors []filmoteka.Actor
-	if err := a.db.Select(&actors, query); err != nil {
+	var count int
+	if err := a.db.Get(&count, query); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+func (a *ActorDao) SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.Actor, error) {
+	q := getActorSearchQuery(fragment)
+	if fragment.Fuzzy {
+		q.orderBySimilarity(fuzzyName(fragment.Name, fragment.Surname))
+	}
+	q.orderBy("id", false)
+
+	query, args, err := q.build("SELECT %s FROM %s a %s %s %s", actorFields, configs.EnvActorTable(),
+		q.whereClause(), q.orderClause(), q.page(page, limit))
+	if err != nil {
 		return nil, err
 	}
+
+	var actors []filmoteka.Actor
+	if fragment.Fuzzy {
+		err = withSimilarityThreshold(a.db, func(tx *sqlx.Tx) error {
+			return tx.Select(&actors, query, args...)
+		})
+	} else {
+		err = a.db.Select(&actors, query, args...)
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
 	return actors, nil
 }
 
+func (a *ActorDao) CountSearchActor(fragment filmoteka.ActorSearchFragment) (int, error) {
+	q := getActorSearchQuery(fragment)
+	query, args, err := q.build("SELECT COUNT(*) FROM %s a %s", configs.EnvActorTable(), q.whereClause())
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if fragment.Fuzzy {
+		err = withSimilarityThreshold(a.db, func(tx *sqlx.Tx) error {
+			return tx.Get(&count, query, args...)
+		})
+	} else {
+		err = a.db.Get(&count, query, args...)
+	}
+	if err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
 func (a *ActorDao) GetFilmsWithCurActor(actorId int) ([]filmoteka.Film, error) {
-	query := fmt.Sprintf("SELECT f.* FROM %s f INNER JOIN %s s ON s.film_id=f.id WHERE s.actor_id=$1",
-		configs.EnvFilmTable(), configs.EnvStarredTable())
+	query := fmt.Sprintf("SELECT %s FROM %s f INNER JOIN %s s ON s.film_id=f.id WHERE s.actor_id=$1 AND %s",
+		filmFields, configs.EnvFilmTable(), configs.EnvStarredTable(), liveFilm)
 
 	var films []filmoteka.Film
 	if err := a.db.Select(&films, query, actorId); err != nil {
-		return nil, err
+		return nil, dbError(err)
+	}
+	return films, nil
+}
+
+// GetFilmsWithActors loads the filmography of all the given actors with one query.
+func (a *ActorDao) GetFilmsWithActors(actorIds []int) (map[int][]filmoteka.Film, error) {
+	query := fmt.Sprintf(`SELECT s.actor_id, %s FROM %s f INNER JOIN %s s ON s.film_id=f.id 
+		WHERE s.actor_id = ANY($1) AND %s ORDER BY s.actor_id, f.id`,
+		filmFields, configs.EnvFilmTable(), configs.EnvStarredTable(), liveFilm)
+
+	var rows []struct {
+		ActorId int `db:"actor_id"`
+		filmoteka.Film
+	}
+	if err := a.db.Select(&rows, query, pq.Array(actorIds)); err != nil {
+		return nil, dbError(err)
+	}
+
+	films := make(map[int][]filmoteka.Film, len(actorIds))
+	for _, row := range rows {
+		films[row.ActorId] = append(films[row.ActorId], row.Film)
 	}
 	return films, nil
 }
 
+func (a *ActorDao) GetCreditsWithCurActor(actorId int) (filmoteka.FilmCredits, error) {
+	query := fmt.Sprintf("SELECT c.role, %s FROM %s f INNER JOIN %s c ON c.film_id=f.id WHERE c.actor_id=$1 AND %s",
+		filmFields, configs.EnvFilmTable(), configs.EnvCreditTable(), liveFilm)
+
+	var rows []struct {
+		Role string `db:"role"`
+		filmoteka.Film
+	}
+	if err := a.db.Select(&rows, query, actorId); err != nil {
+		return nil, dbError(err)
+	}
+
+	credits := make(filmoteka.FilmCredits)
+	for _, row := range rows {
+		credits[row.Role] = append(credits[row.Role], row.Film)
+	}
+	return credits, nil
+}
+
+// DeleteActorById moves the actor to the trash, the films keep the actor in their cast
+// until the actor is purged.
 func (a *ActorDao) DeleteActorById(tx *sqlx.Tx, id int) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", configs.EnvActorTable())
+	query := fmt.Sprintf("UPDATE %s SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL", configs.EnvActorTable())
 
-	if _, err := tx.Query(query, id); err != nil {
-		return err
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.NotFoundError("actor not found")
 	}
 	return nil
 }
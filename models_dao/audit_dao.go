@@ -0,0 +1,145 @@
+package models_dao
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+)
+
+type AuditDao struct {
+	db *sqlx.DB
+}
+
+func NewAuditDao(db *sqlx.DB) *AuditDao {
+	return &AuditDao{
+		db: db,
+	}
+}
+
+const (
+	// filmSnapshot is the film with its cast and genres, without the service columns for search.
+	filmSnapshot = `
+		SELECT (to_jsonb(f) - 'search_vector' - 'title_norm') || jsonb_build_object(
+			'cast', COALESCE((SELECT jsonb_agg(jsonb_build_object('actor_id', s.actor_id,
+				'character', s.character_name, 'billing', s.billing_order) ORDER BY s.billing_order, s.actor_id)
+				FROM %[2]s s WHERE s.film_id=f.id), '[]'),
+			'genres', COALESCE((SELECT jsonb_agg(g.name ORDER BY g.name)
+				FROM %[3]s fg
+				INNER JOIN %[4]s g ON (fg.genre_id=g.id)
+				WHERE fg.film_id=f.id), '[]'))
+		FROM %[1]s f WHERE f.id=$1
+		`
+	actorSnapshot = "SELECT to_jsonb(a) - 'name_norm' - 'surname_norm' FROM %s a WHERE a.id=$1"
+	// userSnapshot never puts the password hash into the log.
+	userSnapshot = "SELECT to_jsonb(u) - 'password' FROM %s u WHERE u.id=$1"
+	// auditFields read a missing snapshot as JSON null, as json.RawMessage can not be scanned from NULL.
+	auditFields = "id, user_id, entity, entity_id, action, COALESCE(before, 'null') AS before, " +
+		"COALESCE(after, 'null') AS after, created_at"
+)
+
+var auditColumns = columns{"entity": "entity", "entity_id": "entity_id", "user_id": "user_id",
+	"created_at": "created_at", "id": "id"}
+
+func getSnapshotQuery(entity string) (string, error) {
+	switch entity {
+	case filmoteka.AuditFilm:
+		return fmt.Sprintf(filmSnapshot, configs.EnvFilmTable(), configs.EnvStarredTable(),
+			configs.EnvFilmGenreTable(), configs.EnvGenreTable()), nil
+	case filmoteka.AuditActor:
+		return fmt.Sprintf(actorSnapshot, configs.EnvActorTable()), nil
+	case filmoteka.AuditUser:
+		return fmt.Sprintf(userSnapshot, configs.EnvUserTable()), nil
+	}
+	return "", filmoteka.ValidationError("unknown entity", map[string]string{"entity": entity})
+}
+
+func getAuditQuery(filter filmoteka.AuditFilter) *query {
+	q := newQuery(auditColumns)
+	if filter.Entity != nil {
+		q.whereEq("entity", *filter.Entity)
+	}
+	if filter.EntityId != nil {
+		q.whereEq("entity_id", *filter.EntityId)
+	}
+	if filter.UserId != nil {
+		q.whereEq("user_id", *filter.UserId)
+	}
+	if filter.From != nil {
+		q.where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q.where("created_at < ?", *filter.To)
+	}
+	return q
+}
+
+// Snapshot reads the entity in the transaction as JSON, it is nil when there is no such entity.
+func (a *AuditDao) Snapshot(tx *sqlx.Tx, entity string, id int) (json.RawMessage, error) {
+	query, err := getSnapshotQuery(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot json.RawMessage
+	if err := tx.Get(&snapshot, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, dbError(err)
+	}
+	return snapshot, nil
+}
+
+func (a *AuditDao) AddAuditEntry(tx *sqlx.Tx, entry filmoteka.AuditEntry) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, entity, entity_id, action, before, after) values ($1,$2,$3,$4,$5,$6)",
+		configs.EnvAuditTable())
+
+	if _, err := tx.Exec(query, entry.UserId, entry.Entity, entry.EntityId, entry.Action,
+		nullJSON(entry.Before), nullJSON(entry.After)); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+// GetAuditLog returns a page of the entries selected by the filter, the latest first.
+func (a *AuditDao) GetAuditLog(filter filmoteka.AuditFilter, page, limit int) ([]filmoteka.AuditEntry, error) {
+	q := getAuditQuery(filter).orderBy("created_at", true).orderBy("id", true)
+	query, args, err := q.build("SELECT %s FROM %s %s %s %s", auditFields, configs.EnvAuditTable(), q.whereClause(),
+		q.orderClause(), q.page(page, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []filmoteka.AuditEntry
+	if err := a.db.Select(&entries, query, args...); err != nil {
+		return nil, dbError(err)
+	}
+	return entries, nil
+}
+
+func (a *AuditDao) CountAuditLog(filter filmoteka.AuditFilter) (int, error) {
+	q := getAuditQuery(filter)
+	query, args, err := q.build("SELECT COUNT(*) FROM %s %s", configs.EnvAuditTable(), q.whereClause())
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := a.db.Get(&count, query, args...); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+// nullJSON passes a missing snapshot as NULL and a present one as text, the driver would
+// send a []byte as bytea.
+func nullJSON(snapshot json.RawMessage) interface{} {
+	if snapshot == nil {
+		return nil
+	}
+	return string(snapshot)
+}
@@ -0,0 +1,137 @@
+package models_dao
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+)
+
+type CollectionDao struct {
+	db *sqlx.DB
+}
+
+func NewCollectionDao(db *sqlx.DB) *CollectionDao {
+	return &CollectionDao{
+		db: db,
+	}
+}
+
+func (c *CollectionDao) CreateCollection(tx *sqlx.Tx, collection filmoteka.Collection) (int, error) {
+	query := fmt.Sprintf("INSERT INTO %s (owner_id, title, description, is_public) values ($1, $2, $3, $4) RETURNING id",
+		configs.EnvCollectionTable())
+
+	var id int
+	row := tx.QueryRow(query, collection.OwnerId, collection.Title, collection.Description, collection.IsPublic)
+	if err := row.Scan(&id); err != nil {
+		return 0, dbError(err)
+	}
+	return id, nil
+}
+
+func (c *CollectionDao) UpdateCollection(tx *sqlx.Tx, collection filmoteka.UpdateCollectionInput) error {
+	q := newQuery(collectionColumns).setAll(collection.UpdateValues())
+	query, args, err := q.build("UPDATE %s SET %s WHERE id=%s", configs.EnvCollectionTable(),
+		q.setClause(), q.bind(*collection.Id))
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(query, args...); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+func (c *CollectionDao) SetCollectionFilms(tx *sqlx.Tx, collectionId int, filmIds ...int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE collection_id=$1", configs.EnvCollectionFilmTable())
+
+	if _, err := tx.Exec(query, collectionId); err != nil {
+		return dbError(err)
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (collection_id, film_id, position) values ($1, $2, $3)",
+		configs.EnvCollectionFilmTable())
+
+	for i, fid := range filmIds {
+		if err := lockLiveFilm(tx, fid, shareLock); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(query, collectionId, fid, i+1); err != nil {
+			return dbError(err)
+		}
+	}
+	return nil
+}
+
+func (c *CollectionDao) GetCollectionById(id int) (filmoteka.Collection, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id=$1", configs.EnvCollectionTable())
+
+	var collection filmoteka.Collection
+	if err := c.db.Get(&collection, query, id); err != nil {
+		return filmoteka.Collection{}, dbError(err)
+	}
+	return collection, nil
+}
+
+func (c *CollectionDao) GetCollectionFilms(collectionId int) ([]filmoteka.Film, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s f INNER JOIN %s cf ON cf.film_id=f.id WHERE cf.collection_id=$1 AND %s ORDER BY cf.position",
+		filmFields, configs.EnvFilmTable(), configs.EnvCollectionFilmTable(), liveFilm)
+
+	var films []filmoteka.Film
+	if err := c.db.Select(&films, query, collectionId); err != nil {
+		return nil, dbError(err)
+	}
+	return films, nil
+}
+
+func (c *CollectionDao) GetPublicCollections(page, limit int) ([]filmoteka.Collection, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE is_public ORDER BY created_at DESC LIMIT $1 OFFSET $2",
+		configs.EnvCollectionTable())
+
+	var collections []filmoteka.Collection
+	if err := c.db.Select(&collections, query, limit, limit*(page-1)); err != nil {
+		return nil, dbError(err)
+	}
+	return collections, nil
+}
+
+func (c *CollectionDao) CountPublicCollections() (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE is_public", configs.EnvCollectionTable())
+
+	var count int
+	if err := c.db.Get(&count, query); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+func (c *CollectionDao) GetUserCollections(ownerId, page, limit int) ([]filmoteka.Collection, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE owner_id=$1 ORDER BY created_at DESC LIMIT $2 OFFSET $3",
+		configs.EnvCollectionTable())
+
+	var collections []filmoteka.Collection
+	if err := c.db.Select(&collections, query, ownerId, limit, limit*(page-1)); err != nil {
+		return nil, dbError(err)
+	}
+	return collections, nil
+}
+
+func (c *CollectionDao) CountUserCollections(ownerId int) (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE owner_id=$1", configs.EnvCollectionTable())
+
+	var count int
+	if err := c.db.Get(&count, query, ownerId); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+func (c *CollectionDao) DeleteCollectionById(tx *sqlx.Tx, id int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", configs.EnvCollectionTable())
+
+	if _, err := tx.Exec(query, id); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package models_dao
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jorgini/filmoteka"
+	"github.com/lib/pq"
+	"regexp"
+)
+
+// postgres error codes https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	checkViolation      = "23514"
+	notNullViolation    = "23502"
+	stringTooLong       = "22001"
+	invalidDatetime     = "22008"
+)
+
+var keyDetail = regexp.MustCompile(`Key \(([^)]+)\)=`)
+
+// dbError translates the errors of the driver into typed errors, the original one stays
+// available through errors.Is and errors.As.
+func dbError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return &filmoteka.Error{Code: filmoteka.CodeNotFound, Message: "requested record not found", Err: err}
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	details := make(map[string]string)
+	field := pqErr.Column
+	if match := keyDetail.FindStringSubmatch(pqErr.Detail); match != nil {
+		field = match[1]
+	}
+
+	switch pqErr.Code {
+	case uniqueViolation:
+		if field != "" {
+			details[field] = "already exists"
+		}
+		return &filmoteka.Error{Code: filmoteka.CodeConflict, Message: "record already exists", Details: details,
+			Err: err}
+	case foreignKeyViolation:
+		if field != "" {
+			details[field] = "references a missing record"
+		}
+		return &filmoteka.Error{Code: filmoteka.CodeValidation, Message: "referenced record not found",
+			Details: details, Err: err}
+	case checkViolation, notNullViolation, stringTooLong, invalidDatetime:
+		if field != "" {
+			details[field] = "invalid value"
+		} else if pqErr.Constraint != "" {
+			details[pqErr.Constraint] = "violated"
+		}
+		return &filmoteka.Error{Code: filmoteka.CodeValidation, Message: "invalid value for field(s)",
+			Details: details, Err: err}
+	}
+	return err
+}
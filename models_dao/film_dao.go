@@ -1,6 +1,7 @@
 package models_dao
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
@@ -8,7 +9,6 @@ import (
 	"github.com/jorgini/filmoteka/configs"
 	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
-	"strings"
 )
 
 type FilmDao struct {
@@ -22,29 +22,136 @@ func NewFilmDao(db *sqlx.DB) *FilmDao {
 }
 
 const (
+	// filmFields are the columns of filmoteka.Film, the films table keeps also the
+	// service columns for search.
+	filmFields = "f.id, f.title, f.description, f.issue_date, f.rating, f.avg_rating, f.votes"
+	// liveFilm and liveActor leave out the films and actors in the trash.
+	liveFilm  = "f.deleted_at IS NULL"
+	liveActor = "a.deleted_at IS NULL"
+	// shareLock keeps a row out of the trash while other rows are linked to it, updateLock
+	// also keeps it from being changed by another transaction.
+	shareLock   = "FOR SHARE"
+	updateLock  = "FOR UPDATE"
 	searchQuery = `
-		SELECT DISTINCT f.* 
+		SELECT DISTINCT %s 
 		FROM %s s
 		INNER JOIN %s f ON (s.film_id=f.id) 
 		INNER JOIN %s a ON (s.actor_id=a.id)
-		WHERE %s
-		LIMIT $1
-		OFFSET $2
+		%s
+		ORDER BY f.id
+		%s
+		`
+	genreCondition = `
+		EXISTS (SELECT 1 FROM %s fg 
+		INNER JOIN %s g ON (fg.genre_id=g.id) 
+		WHERE fg.film_id=f.id AND g.name=?)
+		`
+	countSearchQuery = `
+		SELECT COUNT(DISTINCT f.id)
+		FROM %s s
+		INNER JOIN %s f ON (s.film_id=f.id) 
+		INNER JOIN %s a ON (s.actor_id=a.id)
+		%s
+		`
+	// fuzzySearchQuery orders the films by the best similarity of their cast to the searched name.
+	fuzzySearchQuery = `
+		SELECT %[1]s FROM %[2]s f
+		INNER JOIN (SELECT s.film_id, MAX(%[5]s) AS score
+			FROM %[3]s s
+			INNER JOIN %[4]s a ON (s.actor_id=a.id)
+			WHERE a.deleted_at IS NULL AND %[6]s
+			GROUP BY s.film_id) m ON (m.film_id=f.id)
+		%[7]s
+		ORDER BY m.score DESC, f.id
+		%[8]s
+		`
+	countFuzzySearchQuery = `
+		SELECT COUNT(*) FROM %[1]s f
+		INNER JOIN (SELECT DISTINCT s.film_id
+			FROM %[2]s s
+			INNER JOIN %[3]s a ON (s.actor_id=a.id)
+			WHERE a.deleted_at IS NULL AND %[4]s) m ON (m.film_id=f.id)
+		%[5]s
+		`
+	// fullTextQuery ranks the films matching the query in %[3]s and makes the snippets
+	// only for the films of the requested page.
+	fullTextQuery = `
+		SELECT p.*, film_search_headline(p.title, %[3]s) AS title_snippet,
+			film_search_headline(p.description, %[3]s) AS snippet
+		FROM (SELECT %[1]s, ts_rank(f.search_vector, film_search_query(%[3]s)) AS rank
+			FROM %[2]s f
+			%[4]s
+			ORDER BY rank DESC, f.id
+			%[5]s) p
+		ORDER BY p.rank DESC, p.id
 		`
 )
 
-func getFilmSearchCondition(fragment filmoteka.FilmSearchFragment) string {
-	condition := make([]string, 0, 3)
+// filmSearchColumns are the columns of the join of films with their cast.
+var filmSearchColumns = columns{"title": "f.title", "name": "a.name", "surname": "a.surname",
+	"full_name": fullNameColumn, "title_norm": "f.title_norm", "name_norm": "a.name_norm",
+	"surname_norm": "a.surname_norm"}
+
+func getGenreCondition() string {
+	return fmt.Sprintf(genreCondition, configs.EnvFilmGenreTable(), configs.EnvGenreTable())
+}
+
+// lockLiveFilm fails with not found for a film missing or in the trash and keeps the film
+// out of the trash until the end of the transaction, so nothing is linked to a deleted film.
+func lockLiveFilm(tx *sqlx.Tx, id int, lock string) error {
+	query := fmt.Sprintf("SELECT f.id FROM %s f WHERE f.id=$1 AND %s %s", configs.EnvFilmTable(), liveFilm, lock)
+
+	var ids []int
+	if err := tx.Select(&ids, query, id); err != nil {
+		return dbError(err)
+	} else if len(ids) == 0 {
+		return filmoteka.NotFoundError("film not found")
+	}
+	return nil
+}
+
+// newFilmQuery starts a query on the films that are not deleted.
+func newFilmQuery(cols columns) *query {
+	return newQuery(cols).where(liveFilm)
+}
+
+// filterByGenre keeps the films of the genre, if it is given.
+func filterByGenre(q *query, genre *string) *query {
+	if genre != nil {
+		q.where(getGenreCondition(), *genre)
+	}
+	return q
+}
+
+// getFullTextQuery matches the films against the search text bound to the returned placeholder.
+func getFullTextQuery(search string, genre *string) (*query, string) {
+	q := newFilmQuery(filmColumns)
+	match := q.bind(search)
+	q.where("f.search_vector @@ film_search_query(" + match + ")")
+	return filterByGenre(q, genre), match
+}
+
+// filterFuzzyFilmSearch adds the conditions on the film itself, the ones on its cast are
+// put in the subquery by the caller.
+func filterFuzzyFilmSearch(q *query, fragment filmoteka.FilmSearchFragment) *query {
+	if fragment.Title != nil {
+		q.whereContains("title", *fragment.Title)
+	}
+	return filterByGenre(q, fragment.Genre)
+}
+
+func getFilmSearchQuery(fragment filmoteka.FilmSearchFragment) *query {
+	q := newFilmQuery(filmSearchColumns).where(liveActor)
 	if fragment.Title != nil {
-		condition = append(condition, fmt.Sprintf("POSITION('%s' in f.title)>0", *fragment.Title))
+		q.whereNormalized("title_norm", *fragment.Title)
 	}
 	if fragment.Name != nil {
-		condition = append(condition, fmt.Sprintf("POSITION('%s' in a.name)>0", *fragment.Name))
+		q.whereNormalized("name_norm", *fragment.Name)
 	}
 	if fragment.Surname != nil {
-		condition = append(condition, fmt.Sprintf("POSITION('%s' in a.surname)>0", *fragment.Surname))
+		q.whereNormalized("surname_norm", *fragment.Surname)
 	}
-	return strings.Join(condition, " AND ")
+	return filterByGenre(q, fragment.Genre)
 }
 
 func (f *FilmDao) CreateFilm(tx *sqlx.Tx, film filmoteka.Film) (int, error) {
@@ -54,113 +161,569 @@ func (f *FilmDao) CreateFilm(tx *sqlx.Tx, film filmoteka.Film) (int, error) {
 	var id int
 	row := tx.QueryRow(query, film.Title, film.Description, film.IssueDate.String(), film.Rating)
 	if err := row.Scan(&id); err != nil {
-		return 0, err
+		return 0, dbError(err)
 	}
 
 	return id, nil
 }
 
-func (f *FilmDao) AddDependency(tx *sqlx.Tx, filmId, actorId int) error {
-	query := fmt.Sprintf("INSERT INTO %s (actor_id,film_id) values ($1,$2)", configs.EnvStarredTable())
+func (f *FilmDao) AddDependency(tx *sqlx.Tx, filmId, actorId int, character string, billing int) error {
+	query := fmt.Sprintf(`INSERT INTO %s (actor_id,film_id,character_name,billing_order) values ($1,$2,$3,$4) 
+		ON CONFLICT (actor_id,film_id) DO UPDATE SET character_name=EXCLUDED.character_name, billing_order=EXCLUDED.billing_order`,
+		configs.EnvStarredTable())
 
-	if _, err := tx.Exec(query, actorId, filmId); err != nil {
+	if _, err := tx.Exec(query, actorId, filmId, character, billing); err != nil {
 		logrus.Infof(query)
-		return err
+		return dbError(err)
 	}
 	return nil
 }
 
+// LockFilm fails with not found for a film missing or in the trash, otherwise nobody else
+// can change or delete the film until the end of the transaction.
+func (f *FilmDao) LockFilm(tx *sqlx.Tx, id int) error {
+	return lockLiveFilm(tx, id, updateLock)
+}
+
 func (f *FilmDao) UpdateFilm(tx *sqlx.Tx, film filmoteka.UpdateFilmInput) error {
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=%d", configs.EnvFilmTable(), film.GetValuesUpdate(), *film.Id)
+	q := newQuery(filmColumns).setAll(film.UpdateValues())
+	query, args, err := q.build("UPDATE %s SET %s WHERE id=%s AND deleted_at IS NULL", configs.EnvFilmTable(),
+		q.setClause(), q.bind(*film.Id))
+	if err != nil {
+		return err
+	}
 
-	if _, err := tx.Exec(query, film.GetArgsUpdate()...); err != nil {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
 		logrus.Info(query)
-		return err
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.NotFoundError("film not found")
 	}
 	return nil
 }
 
-func (f *FilmDao) UpdateDependencies(tx *sqlx.Tx, filmId int, actorIds ...int) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE film_id=$1 AND array_position($2, actor_id)=NULL",
+func (f *FilmDao) PruneDependencies(tx *sqlx.Tx, filmId int, actorIds ...int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE film_id=$1 AND array_position($2, actor_id) IS NULL",
 		configs.EnvStarredTable())
 
 	if _, err := tx.Exec(query, filmId, pq.Array(actorIds)); err != nil {
-		return err
+		return dbError(err)
 	}
+	return nil
+}
 
-	query = fmt.Sprintf("INSERT INTO %s (film_id, actor_id) values ($1, $2) ON CONFLICT DO NOTHING",
-		configs.EnvStarredTable())
-
-	for _, aid := range actorIds {
-		if _, err := tx.Exec(query, filmId, aid); err != nil {
-			logrus.Info(query)
-			return err
+// GetSortedFilmList returns a page of films sorted by the sortBy column. When after is
+// not 0 the page starts right after the film with this id instead of skipping the
+// previous pages, so deep pages are read from the index as fast as the first one.
+func (f *FilmDao) GetSortedFilmList(sort []filmoteka.SortKey, genre *string, after, page, limit int) ([]filmoteka.Film, error) {
+	q := newFilmQuery(filmColumns)
+	if after != 0 {
+		cursor, err := f.getCursor(sort, after)
+		if err != nil {
+			return nil, err
 		}
+		q.whereAfter(sort, cursor)
+		page = 1
 	}
-	return nil
-}
+	filterByGenre(q, genre).orderByKeys(sort)
 
-func (f *FilmDao) GetSortedFilmList(sortBy string, page, limit int) ([]filmoteka.Film, error) {
-	query := fmt.Sprintf("SELECT * FROM %s ORDER BY %s DESC LIMIT $1 OFFSET $2", configs.EnvFilmTable(), sortBy)
+	query, args, err := q.build("SELECT %s FROM %s f %s %s %s", filmFields, configs.EnvFilmTable(),
+		q.whereClause(), q.orderClause(), q.page(page, limit))
+	if err != nil {
+		return nil, err
+	}
 
 	var films []filmoteka.Film
-	if err := f.db.Select(&films, query, limit, limit*(page-1)); err != nil {
-		return nil, err
+	if err := f.db.Select(&films, query, args...); err != nil {
+		return nil, dbError(err)
 	}
 	return films, nil
 }
 
-func (f *FilmDao) GetFilmListByTitle(page, limit int, title string) ([]filmoteka.Film, error) {
-	query := fmt.Sprintf("SELECT * FROM %s WHERE POSITION('%s' in title) > 0 LIMIT $1 OFFSET $2",
-		configs.EnvFilmTable(), title)
+// getCursor reads the values of the sort keys of the film a list continues after. A film
+// deleted since the previous page was read can not continue the list.
+func (f *FilmDao) getCursor(sort []filmoteka.SortKey, id int) ([]interface{}, error) {
+	q := newFilmQuery(filmColumns).where("f.id=?", id)
+	query, args, err := q.build("SELECT %s FROM %s f %s", q.keyColumns(sort), configs.EnvFilmTable(), q.whereClause())
+	if err != nil {
+		return nil, err
+	}
 
-	var films []filmoteka.Film
-	if err := f.db.Select(&films, query, limit, limit*(page-1)); err != nil {
+	values, err := f.db.QueryRowx(query, args...).SliceScan()
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, filmoteka.ValidationError("film to continue the list after not found",
+			map[string]string{"after": "film not found"})
+	} else if err != nil {
+		return nil, dbError(err)
+	}
+
+	// numeric columns are scanned as bytes and are bound back as text
+	for i, value := range values {
+		if text, ok := value.([]byte); ok {
+			values[i] = string(text)
+		}
+	}
+	return values, nil
+}
+
+func (f *FilmDao) CountFilms(genre *string) (int, error) {
+	q := filterByGenre(newFilmQuery(filmColumns), genre)
+	query, args, err := q.build("SELECT COUNT(*) FROM %s f %s", configs.EnvFilmTable(), q.whereClause())
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := f.db.Get(&count, query, args...); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+func (f *FilmDao) GetFilmListByTitle(page, limit int, title string, genre *string) ([]filmoteka.Film, error) {
+	q := filterByGenre(newFilmQuery(filmColumns).whereNormalized("title_norm", title), genre).orderBy("id", false)
+	query, args, err := q.build("SELECT %s FROM %s f %s %s %s", filmFields, configs.EnvFilmTable(),
+		q.whereClause(), q.orderClause(), q.page(page, limit))
+	if err != nil {
 		return nil, err
 	}
+
+	var films []filmoteka.Film
+	if err := f.db.Select(&films, query, args...); err != nil {
+		return nil, dbError(err)
+	}
 	return films, nil
 }
 
+func (f *FilmDao) CountFilmsByTitle(title string, genre *string) (int, error) {
+	q := filterByGenre(newFilmQuery(filmColumns).whereNormalized("title_norm", title), genre)
+	query, args, err := q.build("SELECT COUNT(*) FROM %s f %s", configs.EnvFilmTable(), q.whereClause())
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := f.db.Get(&count, query, args...); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
 func (f *FilmDao) GetCurFilm(id int) (filmoteka.Film, error) {
-	query := fmt.Sprintf("SELECT * FROM %s WHERE id=$1", configs.EnvFilmTable())
+	query := fmt.Sprintf("SELECT %s FROM %s f WHERE f.id=$1 AND %s", filmFields, configs.EnvFilmTable(), liveFilm)
 
 	var film []filmoteka.Film
 	if err := f.db.Select(&film, query, id); err != nil {
-		return filmoteka.Film{}, err
+		return filmoteka.Film{}, dbError(err)
 	} else if len(film) == 0 {
-		return filmoteka.Film{}, errors.New("no film find")
+		return filmoteka.Film{}, filmoteka.NotFoundError("film not found")
 	}
 	return film[0], nil
 }
 
 func (f *FilmDao) GetActorsInCurFilm(filmId int) ([]filmoteka.InputActor, error) {
-	query := fmt.Sprintf("SELECT a.name, a.surname FROM %s a INNER JOIN %s s ON a.id = s.actor_id WHERE s.film_id=$1",
-		configs.EnvActorTable(), configs.EnvStarredTable())
+	query := fmt.Sprintf(`SELECT a.name, a.surname, s.character_name, s.billing_order FROM %s a 
+		INNER JOIN %s s ON a.id = s.actor_id WHERE s.film_id=$1 AND %s ORDER BY s.billing_order, a.surname, a.name`,
+		configs.EnvActorTable(), configs.EnvStarredTable(), liveActor)
 
 	var actors []filmoteka.InputActor
 	if err := f.db.Select(&actors, query, filmId); err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	return actors, nil
 }
 
+// GetActorsInFilms loads the cast of all the given films with one query.
+func (f *FilmDao) GetActorsInFilms(filmIds []int) (map[int][]filmoteka.InputActor, error) {
+	query := fmt.Sprintf(`SELECT s.film_id, a.name, a.surname, s.character_name, s.billing_order FROM %s a 
+		INNER JOIN %s s ON a.id = s.actor_id WHERE s.film_id = ANY($1) AND %s 
+		ORDER BY s.film_id, s.billing_order, a.surname, a.name`,
+		configs.EnvActorTable(), configs.EnvStarredTable(), liveActor)
+
+	var rows []struct {
+		FilmId int `db:"film_id"`
+		filmoteka.InputActor
+	}
+	if err := f.db.Select(&rows, query, pq.Array(filmIds)); err != nil {
+		return nil, dbError(err)
+	}
+
+	cast := make(map[int][]filmoteka.InputActor, len(filmIds))
+	for _, row := range rows {
+		cast[row.FilmId] = append(cast[row.FilmId], row.InputActor)
+	}
+	return cast, nil
+}
+
 func (f *FilmDao) GetFilmListByActor(page, limit int, fragment filmoteka.FilmSearchFragment) ([]filmoteka.Film, error) {
-	query := fmt.Sprintf(searchQuery, configs.EnvStarredTable(), configs.EnvFilmTable(),
-		configs.EnvActorTable(), getFilmSearchCondition(fragment))
+	if fragment.Fuzzy {
+		return f.getFilmListByActorFuzzy(page, limit, fragment)
+	}
+
+	q := getFilmSearchQuery(fragment)
+	query, args, err := q.build(searchQuery, filmFields, configs.EnvStarredTable(), configs.EnvFilmTable(),
+		configs.EnvActorTable(), q.whereClause(), q.page(page, limit))
+	if err != nil {
+		return nil, err
+	}
 
 	var films []filmoteka.Film
-	if err := f.db.Select(&films, query, limit, limit*(page-1)); err != nil {
+	if err := f.db.Select(&films, query, args...); err != nil {
+		return nil, dbError(err)
+	}
+	return films, nil
+}
+
+func (f *FilmDao) getFilmListByActorFuzzy(page, limit int, fragment filmoteka.FilmSearchFragment) ([]filmoteka.Film, error) {
+	q := newFilmQuery(filmSearchColumns)
+	column, value := fuzzyName(fragment.Name, fragment.Surname)
+	score, similar := q.similarity(column, value), q.similar(column, value)
+	filterFuzzyFilmSearch(q, fragment)
+
+	query, args, err := q.build(fuzzySearchQuery, filmFields, configs.EnvFilmTable(), configs.EnvStarredTable(),
+		configs.EnvActorTable(), score, similar, q.whereClause(), q.page(page, limit))
+	if err != nil {
 		return nil, err
 	}
+
+	var films []filmoteka.Film
+	err = withSimilarityThreshold(f.db, func(tx *sqlx.Tx) error {
+		return tx.Select(&films, query, args...)
+	})
+	if err != nil {
+		return nil, dbError(err)
+	}
 	return films, nil
 }
 
-func (f *FilmDao) DeleteFilmById(tx *sqlx.Tx, id int) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", configs.EnvFilmTable())
+func (f *FilmDao) CountFilmsByActor(fragment filmoteka.FilmSearchFragment) (int, error) {
+	if fragment.Fuzzy {
+		return f.countFilmsByActorFuzzy(fragment)
+	}
 
-	if _, err := tx.Exec(query, id); err != nil {
-		return err
+	q := getFilmSearchQuery(fragment)
+	query, args, err := q.build(countSearchQuery, configs.EnvStarredTable(), configs.EnvFilmTable(),
+		configs.EnvActorTable(), q.whereClause())
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := f.db.Get(&count, query, args...); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+func (f *FilmDao) countFilmsByActorFuzzy(fragment filmoteka.FilmSearchFragment) (int, error) {
+	q := newFilmQuery(filmSearchColumns)
+	similar := q.similar(fuzzyName(fragment.Name, fragment.Surname))
+	filterFuzzyFilmSearch(q, fragment)
+
+	query, args, err := q.build(countFuzzySearchQuery, configs.EnvFilmTable(), configs.EnvStarredTable(),
+		configs.EnvActorTable(), similar, q.whereClause())
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = withSimilarityThreshold(f.db, func(tx *sqlx.Tx) error {
+		return tx.Get(&count, query, args...)
+	})
+	if err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+// SearchFilmsFullText returns a page of the films matching the search text, the best
+// matches first.
+func (f *FilmDao) SearchFilmsFullText(page, limit int, search string, genre *string) ([]filmoteka.FilmSearchResult, error) {
+	q, match := getFullTextQuery(search, genre)
+	query, args, err := q.build(fullTextQuery, filmFields, configs.EnvFilmTable(), match,
+		q.whereClause(), q.page(page, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var films []filmoteka.FilmSearchResult
+	if err := f.db.Select(&films, query, args...); err != nil {
+		return nil, dbError(err)
+	}
+	return films, nil
+}
+
+func (f *FilmDao) CountFilmsFullText(search string, genre *string) (int, error) {
+	q, _ := getFullTextQuery(search, genre)
+	query, args, err := q.build("SELECT COUNT(*) FROM %s f %s", configs.EnvFilmTable(), q.whereClause())
+	if err != nil {
+		return 0, err
 	}
 
+	var count int
+	if err := f.db.Get(&count, query, args...); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+func (f *FilmDao) AddGenreDependency(tx *sqlx.Tx, filmId, genreId int) error {
+	query := fmt.Sprintf("INSERT INTO %s (film_id,genre_id) values ($1,$2)", configs.EnvFilmGenreTable())
+
+	if _, err := tx.Exec(query, filmId, genreId); err != nil {
+		return dbError(err)
+	}
 	return nil
 }
+
+func (f *FilmDao) UpdateGenreDependencies(tx *sqlx.Tx, filmId int, genreIds ...int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE film_id=$1 AND array_position($2, genre_id) IS NULL",
+		configs.EnvFilmGenreTable())
+
+	if _, err := tx.Exec(query, filmId, pq.Array(genreIds)); err != nil {
+		return dbError(err)
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (film_id, genre_id) values ($1, $2) ON CONFLICT DO NOTHING",
+		configs.EnvFilmGenreTable())
+
+	for _, gid := range genreIds {
+		if _, err := tx.Exec(query, filmId, gid); err != nil {
+			return dbError(err)
+		}
+	}
+	return nil
+}
+
+func (f *FilmDao) GetGenresInCurFilm(filmId int) ([]string, error) {
+	query := fmt.Sprintf("SELECT g.name FROM %s g INNER JOIN %s fg ON g.id = fg.genre_id WHERE fg.film_id=$1 ORDER BY g.name",
+		configs.EnvGenreTable(), configs.EnvFilmGenreTable())
+
+	var genres []string
+	if err := f.db.Select(&genres, query, filmId); err != nil {
+		return nil, dbError(err)
+	}
+	return genres, nil
+}
+
+// GetGenresInFilms loads the genres of all the given films with one query.
+func (f *FilmDao) GetGenresInFilms(filmIds []int) (map[int][]string, error) {
+	query := fmt.Sprintf(`SELECT fg.film_id, g.name FROM %s g INNER JOIN %s fg ON g.id = fg.genre_id 
+		WHERE fg.film_id = ANY($1) ORDER BY fg.film_id, g.name`,
+		configs.EnvGenreTable(), configs.EnvFilmGenreTable())
+
+	var rows []struct {
+		FilmId int    `db:"film_id"`
+		Name   string `db:"name"`
+	}
+	if err := f.db.Select(&rows, query, pq.Array(filmIds)); err != nil {
+		return nil, dbError(err)
+	}
+
+	genres := make(map[int][]string, len(filmIds))
+	for _, row := range rows {
+		genres[row.FilmId] = append(genres[row.FilmId], row.Name)
+	}
+	return genres, nil
+}
+
+func (f *FilmDao) AddCredit(tx *sqlx.Tx, filmId, actorId int, role string) error {
+	query := fmt.Sprintf("INSERT INTO %s (actor_id,film_id,role) values ($1,$2,$3) ON CONFLICT DO NOTHING",
+		configs.EnvCreditTable())
+
+	if _, err := tx.Exec(query, actorId, filmId, role); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+func (f *FilmDao) DeleteCredits(tx *sqlx.Tx, filmId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE film_id=$1", configs.EnvCreditTable())
+
+	if _, err := tx.Exec(query, filmId); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+func (f *FilmDao) GetCreditsInCurFilm(filmId int) (filmoteka.Credits, error) {
+	query := fmt.Sprintf("SELECT c.role, a.name, a.surname FROM %s a INNER JOIN %s c ON a.id = c.actor_id WHERE c.film_id=$1 AND %s",
+		configs.EnvActorTable(), configs.EnvCreditTable(), liveActor)
+
+	var rows []struct {
+		Role string `db:"role"`
+		filmoteka.InputActor
+	}
+	if err := f.db.Select(&rows, query, filmId); err != nil {
+		return nil, dbError(err)
+	}
+
+	credits := make(filmoteka.Credits)
+	for _, row := range rows {
+		credits[row.Role] = append(credits[row.Role], row.InputActor)
+	}
+	return credits, nil
+}
+
+// DeleteFilmById moves the film to the trash, its cast, genres and credits stay linked
+// until the film is purged.
+func (f *FilmDao) DeleteFilmById(tx *sqlx.Tx, id int) error {
+	query := fmt.Sprintf("UPDATE %s SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL", configs.EnvFilmTable())
+
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.NotFoundError("film not found")
+	}
+	return nil
+}
+
+const (
+	facetYear   = "year"
+	facetRating = "rating"
+
+	// castCondition counts how many of the given actors star in the film.
+	castCondition = "(SELECT COUNT(DISTINCT s.actor_id) FROM %s s WHERE s.film_id=f.id AND s.actor_id = ANY(?))"
+	// genresCondition counts how many of the given genres the film has.
+	genresCondition = `
+		(SELECT COUNT(DISTINCT g.name) FROM %s fg 
+		INNER JOIN %s g ON (fg.genre_id=g.id) 
+		WHERE fg.film_id=f.id AND g.name = ANY(?))
+		`
+	excludeTermCondition = "POSITION(lower(?) in lower(f.title || ' ' || COALESCE(f.description, '')))=0"
+	yearFacetQuery       = `
+		SELECT %s AS bucket_from, %s AS bucket_to, COUNT(*) AS count
+		FROM %s f
+		%s
+		GROUP BY 1, 2
+		ORDER BY 1
+		`
+	// ratingFacetQuery puts every film into the bucket of the whole point of its average
+	// rating, so there are at most 11 buckets whatever the ratings are.
+	ratingFacetQuery = `
+		SELECT floor(f.avg_rating)::int AS bucket_from, floor(f.avg_rating)::int AS bucket_to, COUNT(*) AS count
+		FROM %s f
+		%s
+		GROUP BY 1, 2
+		ORDER BY 1
+		`
+)
+
+// matchCount is the condition on the number of matched items: all of them, any or none.
+func matchCount(count string, match string, items int) (string, []interface{}) {
+	if match == filmoteka.MatchAny {
+		return count + ">0", nil
+	}
+	return count + "=?", []interface{}{items}
+}
+
+// getFilmFilterQuery applies the filter to the films. The range of the facet being
+// counted is left out, so its buckets show the films of every other range.
+func getFilmFilterQuery(filter filmoteka.FilmFilter, facet string) *query {
+	q := newFilmQuery(filmColumns)
+	if filter.Title != nil {
+		q.whereContains("title", *filter.Title)
+	}
+	if facet != facetYear {
+		if filter.YearFrom != nil {
+			q.where("f.issue_date >= make_date(?, 1, 1)", *filter.YearFrom)
+		}
+		if filter.YearTo != nil {
+			q.where("f.issue_date < make_date(?, 1, 1)", *filter.YearTo+1)
+		}
+	}
+	if facet != facetRating {
+		if filter.RatingMin != nil {
+			q.where("f.avg_rating >= ?", *filter.RatingMin)
+		}
+		if filter.RatingMax != nil {
+			q.where("f.avg_rating < ?", *filter.RatingMax+1)
+		}
+	}
+
+	genres := fmt.Sprintf(genresCondition, configs.EnvFilmGenreTable(), configs.EnvGenreTable())
+	cast := fmt.Sprintf(castCondition, configs.EnvStarredTable())
+	if len(filter.Genres) != 0 {
+		condition, values := matchCount(genres, filter.GenreMatch, len(filter.Genres))
+		q.where(condition, append([]interface{}{pq.Array(filter.Genres)}, values...)...)
+	}
+	if len(filter.Actors) != 0 {
+		condition, values := matchCount(cast, filter.ActorMatch, len(filter.Actors))
+		q.where(condition, append([]interface{}{pq.Array(filter.Actors)}, values...)...)
+	}
+	if len(filter.ExcludeGenres) != 0 {
+		q.where(genres+"=0", pq.Array(filter.ExcludeGenres))
+	}
+	if len(filter.ExcludeActors) != 0 {
+		q.where(cast+"=0", pq.Array(filter.ExcludeActors))
+	}
+	for _, term := range filter.ExcludeTerms {
+		q.where(excludeTermCondition, term)
+	}
+	return q
+}
+
+// GetFilteredFilmList returns a page of the filtered films, the best rated first.
+func (f *FilmDao) GetFilteredFilmList(page, limit int, filter filmoteka.FilmFilter) ([]filmoteka.Film, error) {
+	q := getFilmFilterQuery(filter, "").orderBy("avg_rating", true).orderBy("id", true)
+	query, args, err := q.build("SELECT %s FROM %s f %s %s %s", filmFields, configs.EnvFilmTable(),
+		q.whereClause(), q.orderClause(), q.page(page, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var films []filmoteka.Film
+	if err := f.db.Select(&films, query, args...); err != nil {
+		return nil, dbError(err)
+	}
+	return films, nil
+}
+
+func (f *FilmDao) CountFilteredFilms(filter filmoteka.FilmFilter) (int, error) {
+	q := getFilmFilterQuery(filter, "")
+	query, args, err := q.build("SELECT COUNT(*) FROM %s f %s", configs.EnvFilmTable(), q.whereClause())
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := f.db.Get(&count, query, args...); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+// GetFilmFacets counts the filtered films by buckets of issue years and by average rating.
+func (f *FilmDao) GetFilmFacets(filter filmoteka.FilmFilter) (filmoteka.Facets, error) {
+	var facets filmoteka.Facets
+
+	q := getFilmFilterQuery(filter, facetYear).where("f.issue_date IS NOT NULL")
+	from := q.expand("(date_part('year', f.issue_date)::int / ?) * ?", []interface{}{filter.YearBucket, filter.YearBucket})
+	to := q.expand("(date_part('year', f.issue_date)::int / ?) * ? + ?",
+		[]interface{}{filter.YearBucket, filter.YearBucket, filter.YearBucket - 1})
+	query, args, err := q.build(yearFacetQuery, from, to, configs.EnvFilmTable(), q.whereClause())
+	if err != nil {
+		return filmoteka.Facets{}, err
+	}
+	if err := f.db.Select(&facets.Years, query, args...); err != nil {
+		return filmoteka.Facets{}, dbError(err)
+	}
+
+	q = getFilmFilterQuery(filter, facetRating)
+	query, args, err = q.build(ratingFacetQuery, configs.EnvFilmTable(), q.whereClause())
+	if err != nil {
+		return filmoteka.Facets{}, err
+	}
+	if err := f.db.Select(&facets.Ratings, query, args...); err != nil {
+		return filmoteka.Facets{}, dbError(err)
+	}
+	return facets, nil
+}
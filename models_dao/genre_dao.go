@@ -0,0 +1,78 @@
+package models_dao
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+)
+
+type GenreDao struct {
+	db *sqlx.DB
+}
+
+func NewGenreDao(db *sqlx.DB) *GenreDao {
+	return &GenreDao{
+		db: db,
+	}
+}
+
+func (g *GenreDao) CreateGenre(tx *sqlx.Tx, genre filmoteka.Genre) (int, error) {
+	query := fmt.Sprintf("INSERT INTO %s (name) values ($1) RETURNING id", configs.EnvGenreTable())
+
+	var id int
+	row := tx.QueryRow(query, genre.Name)
+	if err := row.Scan(&id); err != nil {
+		return 0, dbError(err)
+	}
+	return id, nil
+}
+
+func (g *GenreDao) UpdateGenre(tx *sqlx.Tx, genre filmoteka.Genre) error {
+	query := fmt.Sprintf("UPDATE %s SET name=$1 WHERE id=$2", configs.EnvGenreTable())
+
+	if _, err := tx.Exec(query, genre.Name, genre.Id); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+func (g *GenreDao) GetGenreId(name string) (int, error) {
+	query := fmt.Sprintf("SELECT id FROM %s WHERE name=$1", configs.EnvGenreTable())
+
+	var id int
+	row := g.db.QueryRow(query, name)
+	if err := row.Scan(&id); err != nil {
+		return 0, dbError(err)
+	}
+	return id, nil
+}
+
+func (g *GenreDao) GetGenreById(id int) (filmoteka.Genre, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id=$1", configs.EnvGenreTable())
+
+	var genre filmoteka.Genre
+	if err := g.db.Get(&genre, query, id); err != nil {
+		return filmoteka.Genre{}, dbError(err)
+	}
+	return genre, nil
+}
+
+func (g *GenreDao) GetGenresList() ([]filmoteka.Genre, error) {
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY name", configs.EnvGenreTable())
+
+	var genres []filmoteka.Genre
+	if err := g.db.Select(&genres, query); err != nil {
+		return nil, dbError(err)
+	}
+	return genres, nil
+}
+
+func (g *GenreDao) DeleteGenreById(tx *sqlx.Tx, id int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", configs.EnvGenreTable())
+
+	if _, err := tx.Exec(query, id); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
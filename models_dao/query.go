@@ -0,0 +1,262 @@
+package models_dao
+
+import (
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// columns whitelists the columns a query may refer to by name, mapping every name to
+// its expression in the query, e.g. "title" to "f.title".
+type columns map[string]string
+
+// fullNameColumn matches the expression of the trigram index on actors.
+const fullNameColumn = "(a.name || ' ' || a.surname)"
+
+var (
+	filmColumns = columns{"id": "f.id", "title": "f.title", "description": "f.description",
+		"issue_date": "f.issue_date", "rating": "f.rating", "avg_rating": "f.avg_rating", "votes": "f.votes",
+		"title_norm": "f.title_norm"}
+	actorColumns = columns{"id": "a.id", "name": "a.name", "surname": "a.surname", "sex": "a.sex",
+		"birthday": "a.birthday", "full_name": fullNameColumn, "name_norm": "a.name_norm",
+		"surname_norm": "a.surname_norm"}
+	collectionColumns = columns{"title": "title", "description": "description", "is_public": "is_public"}
+	reviewColumns     = columns{"rating": "rating", "text": "text", "updated_at": "updated_at"}
+
+	// likeEscaper keeps the wildcards of LIKE typed by the user as plain characters.
+	likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+// query builds the variable parts of a statement. Column names are checked against the
+// whitelist and values are only ever passed as arguments, so user input never becomes
+// part of the SQL text. The first unknown column is kept in err and reported by build.
+type query struct {
+	columns    columns
+	args       []interface{}
+	conditions []string
+	values     []string
+	order      []string
+	err        error
+}
+
+// newQuery starts a query whose statement already uses args as $1..$n.
+func newQuery(cols columns, args ...interface{}) *query {
+	return &query{columns: cols, args: args}
+}
+
+func (q *query) column(name string) string {
+	expr, ok := q.columns[name]
+	if !ok && q.err == nil {
+		q.err = filmoteka.ValidationError("unknown column", map[string]string{"column": name})
+	}
+	return expr
+}
+
+// bind adds value to the arguments and returns its placeholder.
+func (q *query) bind(value interface{}) string {
+	q.args = append(q.args, value)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+// expand binds values to the ? marks of a template written in the DAO itself.
+func (q *query) expand(template string, values []interface{}) string {
+	if strings.Count(template, "?") != len(values) {
+		panic(fmt.Sprintf("query template %q expects %d values, got %d",
+			template, strings.Count(template, "?"), len(values)))
+	}
+
+	var sb strings.Builder
+	for _, part := range strings.SplitAfter(template, "?") {
+		if strings.HasSuffix(part, "?") {
+			sb.WriteString(strings.TrimSuffix(part, "?"))
+			sb.WriteString(q.bind(values[0]))
+			values = values[1:]
+		} else {
+			sb.WriteString(part)
+		}
+	}
+	return sb.String()
+}
+
+// where adds a condition written in the DAO, every ? in it is bound to the next value.
+func (q *query) where(condition string, values ...interface{}) *query {
+	q.conditions = append(q.conditions, q.expand(condition, values))
+	return q
+}
+
+func (q *query) whereEq(column string, value interface{}) *query {
+	q.conditions = append(q.conditions, q.column(column)+"="+q.bind(value))
+	return q
+}
+
+// whereContains keeps the rows where the column contains fragment as a substring.
+func (q *query) whereContains(column, fragment string) *query {
+	q.conditions = append(q.conditions, fmt.Sprintf("POSITION(%s in %s)>0", q.bind(fragment), q.column(column)))
+	return q
+}
+
+// whereNormalized keeps the rows where the normalised column contains fragment. The
+// fragment is normalised by the same translit_normalize of the database that fills the
+// column, so "Tarkovsky" finds "Тарковский". LIKE rather than POSITION lets the search
+// use the trigram index of the column.
+func (q *query) whereNormalized(column, fragment string) *query {
+	q.conditions = append(q.conditions, fmt.Sprintf("%s LIKE '%%' || translit_normalize(%s) || '%%'",
+		q.column(column), q.bind(likeEscaper.Replace(fragment))))
+	return q
+}
+
+// similar is the condition of the column being similar to value by trigrams. The %
+// operator can use the trigram indexes, its threshold is set by withSimilarityThreshold.
+func (q *query) similar(column, value string) string {
+	return q.column(column) + " % " + q.bind(value)
+}
+
+// similarity is the trigram similarity of the column to value, from 0 to 1.
+func (q *query) similarity(column, value string) string {
+	return fmt.Sprintf("similarity(%s, %s)", q.column(column), q.bind(value))
+}
+
+func (q *query) whereSimilar(column, value string) *query {
+	q.conditions = append(q.conditions, q.similar(column, value))
+	return q
+}
+
+// orderBySimilarity puts the rows most similar to value first.
+func (q *query) orderBySimilarity(column, value string) *query {
+	q.order = append(q.order, q.similarity(column, value)+" DESC")
+	return q
+}
+
+// set adds column=value to the SET list. The column is written by its name, as
+// Postgres does not allow a table alias in the target of an UPDATE.
+func (q *query) set(column string, value interface{}) *query {
+	q.column(column)
+	q.values = append(q.values, column+"="+q.bind(value))
+	return q
+}
+
+// setAll adds all the values to the SET list in the order of their columns.
+func (q *query) setAll(values map[string]interface{}) *query {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		q.set(name, values[name])
+	}
+	return q
+}
+
+// setExpr sets the column to an expression written in the DAO, like now().
+func (q *query) setExpr(column, expr string, values ...interface{}) *query {
+	q.column(column)
+	q.values = append(q.values, column+"="+q.expand(expr, values))
+	return q
+}
+
+func (q *query) orderBy(column string, desc bool) *query {
+	expr := q.column(column)
+	if desc {
+		expr += " DESC"
+	}
+	q.order = append(q.order, expr)
+	return q
+}
+
+// withIdKey adds id in the direction of the last key to the keys, so the rows with equal
+// keys keep the same order on every page.
+func withIdKey(keys []filmoteka.SortKey) []filmoteka.SortKey {
+	id := filmoteka.SortKey{Column: "id"}
+	if len(keys) > 0 {
+		id.Desc = keys[len(keys)-1].Desc
+	}
+	return append(keys[:len(keys):len(keys)], id)
+}
+
+// orderByKeys orders by the keys and then by id, see withIdKey.
+func (q *query) orderByKeys(keys []filmoteka.SortKey) *query {
+	for _, key := range withIdKey(keys) {
+		q.orderBy(key.Column, key.Desc)
+	}
+	return q
+}
+
+// keyColumns lists the expressions of the keys of orderByKeys, to read the values of the
+// keys of a row whereAfter continues after.
+func (q *query) keyColumns(keys []filmoteka.SortKey) string {
+	keys = withIdKey(keys)
+	exprs := make([]string, len(keys))
+	for i, key := range keys {
+		exprs[i] = q.column(key.Column)
+	}
+	return strings.Join(exprs, ", ")
+}
+
+// whereAfter keeps the rows placed after the row with the values of the keyColumns in the
+// order of orderByKeys. Keys of one direction are compared as a row, so the index on them
+// can be scanned right from the row, mixed directions need a condition for every key.
+func (q *query) whereAfter(keys []filmoteka.SortKey, values []interface{}) *query {
+	keys = withIdKey(keys)
+	exprs, binds := make([]string, len(keys)), make([]string, len(keys))
+	same := true
+	for i, key := range keys {
+		exprs[i], binds[i] = q.column(key.Column), q.bind(values[i])
+		same = same && key.Desc == keys[0].Desc
+	}
+
+	op := func(key filmoteka.SortKey) string {
+		if key.Desc {
+			return "<"
+		}
+		return ">"
+	}
+	if same {
+		q.conditions = append(q.conditions, fmt.Sprintf("(%s)%s(%s)", strings.Join(exprs, ", "), op(keys[0]),
+			strings.Join(binds, ", ")))
+		return q
+	}
+
+	var equal, alternatives []string
+	for i, key := range keys {
+		alternatives = append(alternatives, strings.Join(append(equal[:i:i], exprs[i]+op(key)+binds[i]), " AND "))
+		equal = append(equal, exprs[i]+"="+binds[i])
+	}
+	q.conditions = append(q.conditions, "(("+strings.Join(alternatives, ") OR (")+"))")
+	return q
+}
+
+// page binds the limit and the offset of the page with the given number.
+func (q *query) page(page, limit int) string {
+	return fmt.Sprintf("LIMIT %s OFFSET %s", q.bind(limit), q.bind(limit*(page-1)))
+}
+
+func (q *query) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+func (q *query) setClause() string {
+	return strings.Join(q.values, ", ")
+}
+
+func (q *query) orderClause() string {
+	if len(q.order) == 0 {
+		return ""
+	}
+	return "ORDER BY " + strings.Join(q.order, ", ")
+}
+
+// build fills the clauses into the statement with fmt verbs and returns it with the
+// arguments, or the error about the first column missing from the whitelist.
+func (q *query) build(statement string, clauses ...interface{}) (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	return fmt.Sprintf(statement, clauses...), q.args, nil
+}
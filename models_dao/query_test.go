@@ -0,0 +1,192 @@
+package models_dao
+
+import (
+	"github.com/jorgini/filmoteka"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestQuery_build(t *testing.T) {
+	// Init Test Table
+	type buildQuery func() (string, []interface{}, error)
+
+	var (
+		injection  = "'; DROP TABLE films; --"
+		apostrophe = "Ocean's Eleven"
+	)
+
+	tests := []struct {
+		name          string
+		build         buildQuery
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectedError error
+	}{
+		{
+			name: "Injection in where value",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(filmColumns).whereContains("title", injection)
+				return q.build("SELECT f.* FROM films f %s", q.whereClause())
+			},
+			expectedQuery: "SELECT f.* FROM films f WHERE POSITION($1 in f.title)>0",
+			expectedArgs:  []interface{}{injection},
+		},
+		{
+			name: "Apostrophe in where value",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(filmSearchColumns).whereContains("title", apostrophe).whereContains("surname", "O'Neil")
+				return q.build("SELECT f.* FROM films f %s", q.whereClause())
+			},
+			expectedQuery: "SELECT f.* FROM films f WHERE POSITION($1 in f.title)>0 AND POSITION($2 in a.surname)>0",
+			expectedArgs:  []interface{}{apostrophe, "O'Neil"},
+		},
+		{
+			name: "Normalised with wildcards",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(actorColumns).whereNormalized("surname_norm", `Tarkovsky%_\`)
+				return q.build("SELECT a.* FROM actors a %s", q.whereClause())
+			},
+			expectedQuery: "SELECT a.* FROM actors a WHERE a.surname_norm LIKE '%' || translit_normalize($1) || '%'",
+			expectedArgs:  []interface{}{`Tarkovsky\%\_\\`},
+		},
+		{
+			name: "Placeholders in value",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(actorColumns, 10).where("a.id > ?", 1).whereEq("name", "$1 ? %s")
+				return q.build("SELECT a.* FROM actors a %s LIMIT $1", q.whereClause())
+			},
+			expectedQuery: "SELECT a.* FROM actors a WHERE a.id > $2 AND a.name=$3 LIMIT $1",
+			expectedArgs:  []interface{}{10, 1, "$1 ? %s"},
+		},
+		{
+			name: "Order and page",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(filmColumns).where("f.votes >= ?", 10).orderBy("avg_rating", true).orderBy("id", true)
+				return q.build("SELECT f.* FROM films f %s %s %s", q.whereClause(), q.orderClause(), q.page(3, 10))
+			},
+			expectedQuery: "SELECT f.* FROM films f WHERE f.votes >= $1 ORDER BY f.avg_rating DESC, f.id DESC LIMIT $2 OFFSET $3",
+			expectedArgs:  []interface{}{10, 10, 20},
+		},
+		{
+			name: "Sort keys",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(filmColumns).orderByKeys([]filmoteka.SortKey{{Column: "rating", Desc: true}, {Column: "title"}})
+				return q.build("SELECT f.* FROM films f %s", q.orderClause())
+			},
+			expectedQuery: "SELECT f.* FROM films f ORDER BY f.rating DESC, f.title, f.id",
+		},
+		{
+			name: "Keyset after one direction",
+			build: func() (string, []interface{}, error) {
+				keys := []filmoteka.SortKey{{Column: "avg_rating", Desc: true}}
+				q := newQuery(filmColumns).whereAfter(keys, []interface{}{"7.50", 3})
+				return q.build("SELECT f.* FROM films f %s", q.whereClause())
+			},
+			expectedQuery: "SELECT f.* FROM films f WHERE (f.avg_rating, f.id)<($1, $2)",
+			expectedArgs:  []interface{}{"7.50", 3},
+		},
+		{
+			name: "Keyset after mixed keys",
+			build: func() (string, []interface{}, error) {
+				keys := []filmoteka.SortKey{{Column: "rating", Desc: true}, {Column: "title"}}
+				q := newQuery(filmColumns).whereAfter(keys, []interface{}{7, injection, 3})
+				return q.build("SELECT f.* FROM films f %s", q.whereClause())
+			},
+			expectedQuery: "SELECT f.* FROM films f WHERE ((f.rating<$1) OR (f.rating=$1 AND f.title>$2) OR " +
+				"(f.rating=$1 AND f.title=$2 AND f.id>$3))",
+			expectedArgs: []interface{}{7, injection, 3},
+		},
+		{
+			name: "Keyset cursor columns",
+			build: func() (string, []interface{}, error) {
+				keys := []filmoteka.SortKey{{Column: "rating", Desc: true}, {Column: "title"}}
+				q := newQuery(filmColumns).where("f.id=?", 3)
+				return q.build("SELECT %s FROM films f %s", q.keyColumns(keys), q.whereClause())
+			},
+			expectedQuery: "SELECT f.rating, f.title, f.id FROM films f WHERE f.id=$1",
+			expectedArgs:  []interface{}{3},
+		},
+		{
+			name: "Injection in cursor key",
+			build: func() (string, []interface{}, error) {
+				keys := []filmoteka.SortKey{{Column: "(SELECT password FROM users)"}}
+				q := newQuery(filmColumns)
+				return q.build("SELECT %s FROM films f", q.keyColumns(keys))
+			},
+			expectedError: filmoteka.ValidationError("unknown column",
+				map[string]string{"column": "(SELECT password FROM users)"}),
+		},
+		{
+			name: "Injection in sort key",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(actorColumns).orderByKeys([]filmoteka.SortKey{{Column: "surname, (SELECT 1)"}})
+				return q.build("SELECT a.* FROM actors a %s", q.orderClause())
+			},
+			expectedError: filmoteka.ValidationError("unknown column", map[string]string{"column": "surname, (SELECT 1)"}),
+		},
+		{
+			name: "Similarity to hostile value",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(actorColumns).whereSimilar("full_name", injection).orderBySimilarity("full_name", injection)
+				return q.build("SELECT a.* FROM actors a %s %s", q.whereClause(), q.orderClause())
+			},
+			expectedQuery: "SELECT a.* FROM actors a WHERE (a.name || ' ' || a.surname) % $1 " +
+				"ORDER BY similarity((a.name || ' ' || a.surname), $2) DESC",
+			expectedArgs: []interface{}{injection, injection},
+		},
+		{
+			name: "Set values",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(reviewColumns, 1, 2).
+					setAll(map[string]interface{}{"text": injection, "rating": 5}).setExpr("updated_at", "now()")
+				return q.build("UPDATE reviews SET %s WHERE id=$1 AND user_id=$2", q.setClause())
+			},
+			expectedQuery: "UPDATE reviews SET rating=$3, text=$4, updated_at=now() WHERE id=$1 AND user_id=$2",
+			expectedArgs:  []interface{}{1, 2, 5, injection},
+		},
+		{
+			name: "Injection in order column",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(filmColumns).orderBy("title; DROP TABLE films", true)
+				return q.build("SELECT f.* FROM films f %s", q.orderClause())
+			},
+			expectedError: filmoteka.ValidationError("unknown column",
+				map[string]string{"column": "title; DROP TABLE films"}),
+		},
+		{
+			name: "Injection in set column",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(collectionColumns).setAll(map[string]interface{}{"owner_id=1, title": "mine"})
+				return q.build("UPDATE collections SET %s WHERE id=1", q.setClause())
+			},
+			expectedError: filmoteka.ValidationError("unknown column",
+				map[string]string{"column": "owner_id=1, title"}),
+		},
+		{
+			name: "Column of another table",
+			build: func() (string, []interface{}, error) {
+				q := newQuery(actorColumns).whereEq("title", "test")
+				return q.build("SELECT a.* FROM actors a %s", q.whereClause())
+			},
+			expectedError: filmoteka.ValidationError("unknown column", map[string]string{"column": "title"}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Build Query
+			query, args, err := test.build()
+
+			// Assert
+			assert.Equal(t, test.expectedError, err)
+			assert.Equal(t, test.expectedQuery, query)
+			assert.Equal(t, test.expectedArgs, args)
+		})
+	}
+}
+
+func TestQuery_whereTemplate(t *testing.T) {
+	assert.Panics(t, func() {
+		newQuery(filmColumns).where("f.id=? AND f.rating=?", 1)
+	})
+}
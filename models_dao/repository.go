@@ -1,42 +1,143 @@
 package models_dao
 
 import (
+	"encoding/json"
 	"github.com/jmoiron/sqlx"
 	"github.com/jorgini/filmoteka"
+	"time"
 )
 
 type User interface {
 	CreateUser(tx *sqlx.Tx, user filmoteka.User) (int, error)
-	GetUser(login, password string) (filmoteka.User, error)
+	GetUserByLogin(login string) (filmoteka.User, error)
+	UpdatePassword(tx *sqlx.Tx, id int, password string) error
 	DeleteUserById(tx *sqlx.Tx, id int) error
-	ValidateUser(id int) (bool, error)
+	GetUserRole(id int) (string, error)
 	UpdateUser(tx *sqlx.Tx, login, userRole string) error
+	GetTokenVersion(id int) (int, error)
+	IncrementTokenVersion(tx *sqlx.Tx, id int) error
+}
+
+type Token interface {
+	CreateRefreshToken(tx *sqlx.Tx, userId int, tokenHash string, expiresAt time.Time) error
+	GetRefreshToken(tokenHash string) (filmoteka.RefreshToken, error)
+	RevokeRefreshToken(tx *sqlx.Tx, id int) error
+	RevokeUserRefreshTokens(tx *sqlx.Tx, userId int) error
+	RevokeAccessToken(tx *sqlx.Tx, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(jti string) (bool, error)
 }
 
 type Actor interface {
 	CreateActor(tx *sqlx.Tx, actor filmoteka.Actor) (int, error)
+	LockActor(tx *sqlx.Tx, id int) error
 	UpdateActor(tx *sqlx.Tx, actor filmoteka.UpdateActorInput) error
 	GetActorId(name, surname string) (int, error)
 	GetActorById(id int) (filmoteka.Actor, error)
-	GetActorsList(page, limit int) ([]filmoteka.Actor, error)
-	SearchActor(page, limit int, name, surname *string) ([]filmoteka.Actor, error)
+	GetActorsList(sort []filmoteka.SortKey, page, limit int) ([]filmoteka.Actor, error)
+	CountActors() (int, error)
+	SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.Actor, error)
+	CountSearchActor(fragment filmoteka.ActorSearchFragment) (int, error)
 	GetFilmsWithCurActor(actorId int) ([]filmoteka.Film, error)
+	GetFilmsWithActors(actorIds []int) (map[int][]filmoteka.Film, error)
+	GetCreditsWithCurActor(actorId int) (filmoteka.FilmCredits, error)
 	DeleteActorById(tx *sqlx.Tx, id int) error
 }
 
 type Film interface {
 	CreateFilm(tx *sqlx.Tx, film filmoteka.Film) (int, error)
-	AddDependency(tx *sqlx.Tx, filmId, actorId int) error
+	AddDependency(tx *sqlx.Tx, filmId, actorId int, character string, billing int) error
+	LockFilm(tx *sqlx.Tx, id int) error
 	UpdateFilm(tx *sqlx.Tx, film filmoteka.UpdateFilmInput) error
-	UpdateDependencies(tx *sqlx.Tx, filmId int, actorId ...int) error
-	GetSortedFilmList(sortBy string, page, limit int) ([]filmoteka.Film, error)
-	GetFilmListByTitle(page, limit int, title string) ([]filmoteka.Film, error)
+	PruneDependencies(tx *sqlx.Tx, filmId int, actorIds ...int) error
+	AddGenreDependency(tx *sqlx.Tx, filmId, genreId int) error
+	UpdateGenreDependencies(tx *sqlx.Tx, filmId int, genreIds ...int) error
+	GetSortedFilmList(sort []filmoteka.SortKey, genre *string, after, page, limit int) ([]filmoteka.Film, error)
+	CountFilms(genre *string) (int, error)
+	GetFilmListByTitle(page, limit int, title string, genre *string) ([]filmoteka.Film, error)
+	CountFilmsByTitle(title string, genre *string) (int, error)
 	GetCurFilm(id int) (filmoteka.Film, error)
 	GetActorsInCurFilm(filmId int) ([]filmoteka.InputActor, error)
+	GetActorsInFilms(filmIds []int) (map[int][]filmoteka.InputActor, error)
+	GetGenresInCurFilm(filmId int) ([]string, error)
+	GetGenresInFilms(filmIds []int) (map[int][]string, error)
+	AddCredit(tx *sqlx.Tx, filmId, actorId int, role string) error
+	DeleteCredits(tx *sqlx.Tx, filmId int) error
+	GetCreditsInCurFilm(filmId int) (filmoteka.Credits, error)
 	GetFilmListByActor(page, limit int, fragment filmoteka.FilmSearchFragment) ([]filmoteka.Film, error)
+	CountFilmsByActor(fragment filmoteka.FilmSearchFragment) (int, error)
+	SearchFilmsFullText(page, limit int, search string, genre *string) ([]filmoteka.FilmSearchResult, error)
+	CountFilmsFullText(search string, genre *string) (int, error)
+	GetFilteredFilmList(page, limit int, filter filmoteka.FilmFilter) ([]filmoteka.Film, error)
+	CountFilteredFilms(filter filmoteka.FilmFilter) (int, error)
+	GetFilmFacets(filter filmoteka.FilmFilter) (filmoteka.Facets, error)
 	DeleteFilmById(tx *sqlx.Tx, id int) error
 }
 
+type Genre interface {
+	CreateGenre(tx *sqlx.Tx, genre filmoteka.Genre) (int, error)
+	UpdateGenre(tx *sqlx.Tx, genre filmoteka.Genre) error
+	GetGenreId(name string) (int, error)
+	GetGenreById(id int) (filmoteka.Genre, error)
+	GetGenresList() ([]filmoteka.Genre, error)
+	DeleteGenreById(tx *sqlx.Tx, id int) error
+}
+
+type Review interface {
+	CreateReview(tx *sqlx.Tx, review filmoteka.Review) (int, error)
+	UpdateReview(tx *sqlx.Tx, id, userId int, review filmoteka.UpdateReviewInput) (int, error)
+	GetReviewById(id int) (filmoteka.Review, error)
+	GetFilmReviews(filmId, page, limit int) ([]filmoteka.Review, error)
+	CountFilmReviews(filmId int) (int, error)
+	DeleteReview(tx *sqlx.Tx, id, userId int) (int, error)
+	DeleteReviewById(tx *sqlx.Tx, id int) (int, error)
+	RecalculateFilmRating(tx *sqlx.Tx, filmId int) error
+}
+
+type Watchlist interface {
+	AddToWatchlist(tx *sqlx.Tx, entry filmoteka.WatchlistEntry) error
+	RemoveFromWatchlist(tx *sqlx.Tx, userId, filmId int) error
+	GetWatchlist(userId int, status string, page, limit int) ([]filmoteka.WatchlistItem, error)
+	CountWatchlist(userId int, status string) (int, error)
+}
+
+type Collection interface {
+	CreateCollection(tx *sqlx.Tx, collection filmoteka.Collection) (int, error)
+	UpdateCollection(tx *sqlx.Tx, collection filmoteka.UpdateCollectionInput) error
+	SetCollectionFilms(tx *sqlx.Tx, collectionId int, filmIds ...int) error
+	GetCollectionById(id int) (filmoteka.Collection, error)
+	GetCollectionFilms(collectionId int) ([]filmoteka.Film, error)
+	GetPublicCollections(page, limit int) ([]filmoteka.Collection, error)
+	CountPublicCollections() (int, error)
+	GetUserCollections(ownerId, page, limit int) ([]filmoteka.Collection, error)
+	CountUserCollections(ownerId int) (int, error)
+	DeleteCollectionById(tx *sqlx.Tx, id int) error
+}
+
+type Audit interface {
+	Snapshot(tx *sqlx.Tx, entity string, id int) (json.RawMessage, error)
+	AddAuditEntry(tx *sqlx.Tx, entry filmoteka.AuditEntry) error
+	GetAuditLog(filter filmoteka.AuditFilter, page, limit int) ([]filmoteka.AuditEntry, error)
+	CountAuditLog(filter filmoteka.AuditFilter) (int, error)
+}
+
+type Revision interface {
+	AddRevision(tx *sqlx.Tx, revision filmoteka.Revision) error
+	GetRevisions(entity string, entityId, page, limit int) ([]filmoteka.Revision, error)
+	CountRevisions(entity string, entityId int) (int, error)
+	GetRevision(entity string, entityId, version int) (filmoteka.Revision, error)
+}
+
+type Trash interface {
+	GetDeleted(entity *string, page, limit int) ([]filmoteka.DeletedEntity, error)
+	CountDeleted(entity *string) (int, error)
+	Restore(tx *sqlx.Tx, entity string, id int) error
+	Purge(tx *sqlx.Tx, before time.Time) (int, error)
+}
+
+type Suggestion interface {
+	GetSuggestions(prefix string, limit int) ([]filmoteka.Suggestion, error)
+}
+
 type Transaction interface {
 	StartTransaction() (*sqlx.Tx, error)
 	ShutDown(tx *sqlx.Tx, err error) error
@@ -47,6 +148,15 @@ type Repository struct {
 	User
 	Actor
 	Film
+	Genre
+	Review
+	Watchlist
+	Collection
+	Suggestion
+	Audit
+	Revision
+	Trash
+	Token
 	Transaction
 }
 
@@ -55,6 +165,15 @@ func NewRepository(db *sqlx.DB) *Repository {
 		User:        NewUserDao(db),
 		Actor:       NewActorDao(db),
 		Film:        NewFilmDao(db),
+		Genre:       NewGenreDao(db),
+		Review:      NewReviewDao(db),
+		Watchlist:   NewWatchlistDao(db),
+		Collection:  NewCollectionDao(db),
+		Suggestion:  NewSuggestionDao(db),
+		Audit:       NewAuditDao(db),
+		Revision:    NewRevisionDao(db),
+		Trash:       NewTrashDao(db),
+		Token:       NewTokenDao(db),
 		Transaction: NewTransaction(db),
 	}
 }
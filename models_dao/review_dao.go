@@ -0,0 +1,130 @@
+package models_dao
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+)
+
+type ReviewDao struct {
+	db *sqlx.DB
+}
+
+func NewReviewDao(db *sqlx.DB) *ReviewDao {
+	return &ReviewDao{
+		db: db,
+	}
+}
+
+const (
+	recalculateRatingQuery = `
+		UPDATE %[1]s SET
+		avg_rating=COALESCE((SELECT AVG(r.rating) FROM %[2]s r WHERE r.film_id=$1), 0),
+		votes=(SELECT COUNT(*) FROM %[2]s r WHERE r.film_id=$1)
+		WHERE id=$1
+		`
+)
+
+// CreateReview fails with not found when the film is missing or in the trash.
+func (r *ReviewDao) CreateReview(tx *sqlx.Tx, review filmoteka.Review) (int, error) {
+	if err := lockLiveFilm(tx, review.FilmId, shareLock); err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, film_id, rating, text) values ($1, $2, $3, $4) RETURNING id",
+		configs.EnvReviewTable())
+
+	var id int
+	row := tx.QueryRow(query, review.UserId, review.FilmId, review.Rating, review.Text)
+	if err := row.Scan(&id); err != nil {
+		return 0, dbError(err)
+	}
+	return id, nil
+}
+
+func (r *ReviewDao) UpdateReview(tx *sqlx.Tx, id, userId int, review filmoteka.UpdateReviewInput) (int, error) {
+	q := newQuery(reviewColumns, id, userId).setAll(review.UpdateValues()).setExpr("updated_at", "now()")
+	query, args, err := q.build("UPDATE %s SET %s WHERE id=$1 AND user_id=$2 RETURNING film_id",
+		configs.EnvReviewTable(), q.setClause())
+	if err != nil {
+		return 0, err
+	}
+
+	var filmId int
+	row := tx.QueryRow(query, args...)
+	if err := row.Scan(&filmId); err != nil {
+		return 0, dbError(err)
+	}
+	return filmId, nil
+}
+
+func (r *ReviewDao) GetReviewById(id int) (filmoteka.Review, error) {
+	query := fmt.Sprintf("SELECT r.* FROM %s r INNER JOIN %s f ON f.id=r.film_id WHERE r.id=$1 AND %s",
+		configs.EnvReviewTable(), configs.EnvFilmTable(), liveFilm)
+
+	var review filmoteka.Review
+	if err := r.db.Get(&review, query, id); err != nil {
+		return filmoteka.Review{}, dbError(err)
+	}
+	return review, nil
+}
+
+func (r *ReviewDao) GetFilmReviews(filmId, page, limit int) ([]filmoteka.Review, error) {
+	query := fmt.Sprintf(`SELECT r.* FROM %s r INNER JOIN %s f ON f.id=r.film_id WHERE r.film_id=$1 AND %s
+		ORDER BY r.updated_at DESC LIMIT $2 OFFSET $3`, configs.EnvReviewTable(), configs.EnvFilmTable(), liveFilm)
+
+	var reviews []filmoteka.Review
+	if err := r.db.Select(&reviews, query, filmId, limit, limit*(page-1)); err != nil {
+		return nil, dbError(err)
+	}
+	return reviews, nil
+}
+
+func (r *ReviewDao) CountFilmReviews(filmId int) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s r INNER JOIN %s f ON f.id=r.film_id WHERE r.film_id=$1 AND %s`,
+		configs.EnvReviewTable(), configs.EnvFilmTable(), liveFilm)
+
+	var count int
+	if err := r.db.Get(&count, query, filmId); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+func (r *ReviewDao) DeleteReview(tx *sqlx.Tx, id, userId int) (int, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 AND user_id=$2 RETURNING film_id", configs.EnvReviewTable())
+
+	var filmId int
+	row := tx.QueryRow(query, id, userId)
+	if err := row.Scan(&filmId); err != nil {
+		return 0, dbError(err)
+	}
+	return filmId, nil
+}
+
+func (r *ReviewDao) DeleteReviewById(tx *sqlx.Tx, id int) (int, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 RETURNING film_id", configs.EnvReviewTable())
+
+	var filmId int
+	row := tx.QueryRow(query, id)
+	if err := row.Scan(&filmId); err != nil {
+		return 0, dbError(err)
+	}
+	return filmId, nil
+}
+
+func (r *ReviewDao) RecalculateFilmRating(tx *sqlx.Tx, filmId int) error {
+	query := fmt.Sprintf(recalculateRatingQuery, configs.EnvFilmTable(), configs.EnvReviewTable())
+
+	result, err := tx.Exec(query, filmId)
+	if err != nil {
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.NotFoundError("film not found")
+	}
+	return nil
+}
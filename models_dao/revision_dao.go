@@ -0,0 +1,85 @@
+package models_dao
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+)
+
+type RevisionDao struct {
+	db *sqlx.DB
+}
+
+func NewRevisionDao(db *sqlx.DB) *RevisionDao {
+	return &RevisionDao{
+		db: db,
+	}
+}
+
+const (
+	revisionFields = "entity, entity_id, version, user_id, snapshot, created_at"
+	// addRevisionQuery numbers the revision after the latest one of the entity and leaves
+	// out of the snapshot the columns not edited by the users.
+	addRevisionQuery = `
+		INSERT INTO %[1]s (entity, entity_id, version, user_id, snapshot)
+		SELECT $1::varchar, $2::integer, COALESCE(MAX(version), 0) + 1, $3::integer,
+			$4::jsonb - 'id' - 'avg_rating' - 'votes' - 'deleted_at'
+		FROM %[1]s WHERE entity=$1 AND entity_id=$2
+		`
+)
+
+// AddRevision adds the snapshot as the next revision of the entity. The entity row is locked
+// first, so concurrent changes of the entity are numbered one after another.
+func (r *RevisionDao) AddRevision(tx *sqlx.Tx, revision filmoteka.Revision) error {
+	table, err := getEntityTable(revision.Entity)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id=$1 FOR UPDATE", table)
+	if _, err := tx.Exec(query, revision.EntityId); err != nil {
+		return dbError(err)
+	}
+
+	query = fmt.Sprintf(addRevisionQuery, configs.EnvRevisionTable())
+
+	if _, err := tx.Exec(query, revision.Entity, revision.EntityId, revision.UserId,
+		nullJSON(revision.Snapshot)); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+// GetRevisions returns a page of the revisions of the entity, the latest first.
+func (r *RevisionDao) GetRevisions(entity string, entityId, page, limit int) ([]filmoteka.Revision, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE entity=$1 AND entity_id=$2 ORDER BY version DESC LIMIT $3 OFFSET $4",
+		revisionFields, configs.EnvRevisionTable())
+
+	var revisions []filmoteka.Revision
+	if err := r.db.Select(&revisions, query, entity, entityId, limit, limit*(page-1)); err != nil {
+		return nil, dbError(err)
+	}
+	return revisions, nil
+}
+
+func (r *RevisionDao) CountRevisions(entity string, entityId int) (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE entity=$1 AND entity_id=$2", configs.EnvRevisionTable())
+
+	var count int
+	if err := r.db.Get(&count, query, entity, entityId); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+func (r *RevisionDao) GetRevision(entity string, entityId, version int) (filmoteka.Revision, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE entity=$1 AND entity_id=$2 AND version=$3",
+		revisionFields, configs.EnvRevisionTable())
+
+	var revision filmoteka.Revision
+	if err := r.db.Get(&revision, query, entity, entityId, version); err != nil {
+		return filmoteka.Revision{}, dbError(err)
+	}
+	return revision, nil
+}
@@ -0,0 +1,54 @@
+package models_dao
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka/configs"
+	"github.com/sirupsen/logrus"
+	"strconv"
+)
+
+const defaultSimilarityThreshold = 0.3
+
+func similarityThreshold() float64 {
+	value := configs.EnvSimilarityThreshold()
+	if value == "" {
+		return defaultSimilarityThreshold
+	}
+
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold < 0 || threshold > 1 {
+		logrus.Errorf("invalid similarity threshold %q, %v is used", value, defaultSimilarityThreshold)
+		return defaultSimilarityThreshold
+	}
+	return threshold
+}
+
+// withSimilarityThreshold runs read in a transaction where the % operator keeps the
+// names at least as similar as the configured threshold. Setting it only for the
+// transaction keeps other queries on the pooled connection unaffected.
+func withSimilarityThreshold(db *sqlx.DB, read func(tx *sqlx.Tx) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	threshold := strconv.FormatFloat(similarityThreshold(), 'f', -1, 64)
+	if _, err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', $1, true)", threshold); err != nil {
+		return err
+	}
+	return read(tx)
+}
+
+// fuzzyName tells by what column to compare the searched name: the full name when both
+// parts are given, otherwise the given part.
+func fuzzyName(name, surname *string) (string, string) {
+	switch {
+	case name != nil && surname != nil:
+		return "full_name", *name + " " + *surname
+	case name != nil:
+		return "name", *name
+	default:
+		return "surname", *surname
+	}
+}
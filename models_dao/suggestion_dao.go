@@ -0,0 +1,53 @@
+package models_dao
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+	"strings"
+)
+
+type SuggestionDao struct {
+	db *sqlx.DB
+}
+
+func NewSuggestionDao(db *sqlx.DB) *SuggestionDao {
+	return &SuggestionDao{
+		db: db,
+	}
+}
+
+// suggestionQuery looks up the films by the prefix of the title and the actors by the
+// prefix of the full name or of the surname, using the prefix indexes on lower(...). The
+// exact matches go first, then the most popular ones: films with more votes and actors
+// with more films.
+const suggestionQuery = `
+	SELECT s.type, s.id, s.text FROM (
+		(SELECT '%[1]s' AS type, f.id, f.title AS text, lower(f.title)=$1 AS exact, f.votes AS weight
+		FROM %[3]s f
+		WHERE lower(f.title) LIKE $2 AND f.deleted_at IS NULL
+		ORDER BY exact DESC, weight DESC, length(f.title), f.id
+		LIMIT $3)
+		UNION ALL
+		(SELECT '%[2]s', a.id, a.name || ' ' || a.surname, lower(a.name || ' ' || a.surname)=$1,
+			(SELECT COUNT(*) FROM %[5]s si WHERE si.actor_id=a.id)
+		FROM %[4]s a
+		WHERE (lower(a.name || ' ' || a.surname) LIKE $2 OR lower(a.surname) LIKE $2) AND a.deleted_at IS NULL
+		ORDER BY 4 DESC, 5 DESC, length(a.name || ' ' || a.surname), a.id
+		LIMIT $3)) s
+	ORDER BY s.exact DESC, s.weight DESC, length(s.text), s.type, s.id
+	LIMIT $3
+	`
+
+func (s *SuggestionDao) GetSuggestions(prefix string, limit int) ([]filmoteka.Suggestion, error) {
+	prefix = strings.ToLower(prefix)
+	query := fmt.Sprintf(suggestionQuery, filmoteka.SuggestionFilm, filmoteka.SuggestionActor,
+		configs.EnvFilmTable(), configs.EnvActorTable(), configs.EnvStarredTable())
+
+	var suggestions []filmoteka.Suggestion
+	if err := s.db.Select(&suggestions, query, prefix, likeEscaper.Replace(prefix)+"%", limit); err != nil {
+		return nil, dbError(err)
+	}
+	return suggestions, nil
+}
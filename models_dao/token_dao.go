@@ -0,0 +1,90 @@
+package models_dao
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+	"time"
+)
+
+type TokenDao struct {
+	db *sqlx.DB
+}
+
+func NewTokenDao(db *sqlx.DB) *TokenDao {
+	return &TokenDao{
+		db: db,
+	}
+}
+
+func (t *TokenDao) CreateRefreshToken(tx *sqlx.Tx, userId int, tokenHash string, expiresAt time.Time) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, token_hash, expires_at) values ($1, $2, $3)",
+		configs.EnvRefreshTokenTable())
+
+	if _, err := tx.Exec(query, userId, tokenHash, expiresAt); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+func (t *TokenDao) GetRefreshToken(tokenHash string) (filmoteka.RefreshToken, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE token_hash=$1", configs.EnvRefreshTokenTable())
+
+	var token filmoteka.RefreshToken
+	if err := t.db.Get(&token, query, tokenHash); err != nil {
+		return filmoteka.RefreshToken{}, dbError(err)
+	}
+	return token, nil
+}
+
+func (t *TokenDao) RevokeRefreshToken(tx *sqlx.Tx, id int) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked=true WHERE id=$1 AND NOT revoked", configs.EnvRefreshTokenTable())
+
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.ConflictError("refresh token already revoked", nil)
+	}
+	return nil
+}
+
+func (t *TokenDao) RevokeUserRefreshTokens(tx *sqlx.Tx, userId int) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked=true WHERE user_id=$1 AND NOT revoked",
+		configs.EnvRefreshTokenTable())
+
+	if _, err := tx.Exec(query, userId); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+func (t *TokenDao) RevokeAccessToken(tx *sqlx.Tx, jti string, expiresAt time.Time) error {
+	cleanup := fmt.Sprintf("DELETE FROM %s WHERE expires_at < now()", configs.EnvRevokedTokenTable())
+	if _, err := tx.Exec(cleanup); err != nil {
+		return dbError(err)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (jti, expires_at) values ($1, $2) ON CONFLICT (jti) DO NOTHING",
+		configs.EnvRevokedTokenTable())
+
+	if _, err := tx.Exec(query, jti, expiresAt); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+func (t *TokenDao) IsAccessTokenRevoked(jti string) (bool, error) {
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE jti=$1)", configs.EnvRevokedTokenTable())
+
+	var revoked bool
+	row := t.db.QueryRow(query, jti)
+	if err := row.Scan(&revoked); err != nil {
+		return false, dbError(err)
+	}
+	return revoked, nil
+}
@@ -16,11 +16,12 @@ func NewTransaction(db *sqlx.DB) *TransactionDao {
 }
 
 func (t *TransactionDao) StartTransaction() (*sqlx.Tx, error) {
-	return t.db.Beginx()
+	tx, err := t.db.Beginx()
+	return tx, dbError(err)
 }
 
 func (t *TransactionDao) ShutDown(tx *sqlx.Tx, err error) error {
-	if rollErr := tx.Rollback(); err != nil {
+	if rollErr := tx.Rollback(); rollErr != nil {
 		return errors.Join(err, rollErr)
 	}
 	return err
@@ -28,7 +29,7 @@ func (t *TransactionDao) ShutDown(tx *sqlx.Tx, err error) error {
 
 func (t *TransactionDao) Commit(tx *sqlx.Tx) error {
 	if err := tx.Commit(); err != nil {
-		return t.ShutDown(tx, err)
+		return t.ShutDown(tx, dbError(err))
 	}
 	return nil
 }
@@ -0,0 +1,138 @@
+package models_dao
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+	"time"
+)
+
+type TrashDao struct {
+	db *sqlx.DB
+}
+
+func NewTrashDao(db *sqlx.DB) *TrashDao {
+	return &TrashDao{
+		db: db,
+	}
+}
+
+// trashQuery lists the deleted films, actors and users together, %[7]s, %[8]s and %[9]s are
+// the where, order and page clauses of the list.
+const trashQuery = `
+	SELECT * FROM (
+		SELECT '%[4]s' AS entity, f.id, f.title AS name, f.deleted_at FROM %[1]s f WHERE f.deleted_at IS NOT NULL
+		UNION ALL
+		SELECT '%[5]s', a.id, a.name || ' ' || a.surname, a.deleted_at FROM %[2]s a WHERE a.deleted_at IS NOT NULL
+		UNION ALL
+		SELECT '%[6]s', u.id, u.login, u.deleted_at FROM %[3]s u WHERE u.deleted_at IS NOT NULL) t
+	%[7]s
+	%[8]s
+	%[9]s
+	`
+
+var trashColumns = columns{"entity": "t.entity", "deleted_at": "t.deleted_at", "id": "t.id"}
+
+// getEntityTable returns the table keeping the entity.
+func getEntityTable(entity string) (string, error) {
+	switch entity {
+	case filmoteka.AuditFilm:
+		return configs.EnvFilmTable(), nil
+	case filmoteka.AuditActor:
+		return configs.EnvActorTable(), nil
+	case filmoteka.AuditUser:
+		return configs.EnvUserTable(), nil
+	}
+	return "", filmoteka.ValidationError("unknown entity", map[string]string{"entity": entity})
+}
+
+func getTrashQuery(entity *string) *query {
+	q := newQuery(trashColumns)
+	if entity != nil {
+		q.whereEq("entity", *entity)
+	}
+	return q
+}
+
+// buildTrashQuery fills the tables into the list of the deleted entities.
+func buildTrashQuery(q *query, order, page string) (string, []interface{}, error) {
+	return q.build(trashQuery, configs.EnvFilmTable(), configs.EnvActorTable(), configs.EnvUserTable(),
+		filmoteka.AuditFilm, filmoteka.AuditActor, filmoteka.AuditUser, q.whereClause(), order, page)
+}
+
+// GetDeleted returns a page of the deleted entities of the kind, or of all kinds when
+// entity is nil, the latest deleted first.
+func (t *TrashDao) GetDeleted(entity *string, page, limit int) ([]filmoteka.DeletedEntity, error) {
+	q := getTrashQuery(entity).orderBy("deleted_at", true).orderBy("entity", false).orderBy("id", false)
+	query, args, err := buildTrashQuery(q, q.orderClause(), q.page(page, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []filmoteka.DeletedEntity
+	if err := t.db.Select(&deleted, query, args...); err != nil {
+		return nil, dbError(err)
+	}
+	return deleted, nil
+}
+
+func (t *TrashDao) CountDeleted(entity *string) (int, error) {
+	q := getTrashQuery(entity)
+	query, args, err := buildTrashQuery(q, "", "")
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := t.db.Get(&count, "SELECT COUNT(*) FROM ("+query+") d", args...); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+// Restore takes the entity out of the trash. The restore fails with a conflict when a live
+// entity has taken its unique name meanwhile.
+func (t *TrashDao) Restore(tx *sqlx.Tx, entity string, id int) error {
+	table, err := getEntityTable(entity)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL", table)
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.NotFoundError("deleted " + entity + " not found")
+	}
+	return nil
+}
+
+// Purge removes for good the entities deleted before the moment together with everything
+// linked to them and returns how many entities were removed.
+func (t *TrashDao) Purge(tx *sqlx.Tx, before time.Time) (int, error) {
+	var purged int
+	for _, entity := range []string{filmoteka.AuditFilm, filmoteka.AuditActor, filmoteka.AuditUser} {
+		table, err := getEntityTable(entity)
+		if err != nil {
+			return 0, err
+		}
+
+		query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < $1", table)
+		result, err := tx.Exec(query, before)
+		if err != nil {
+			return 0, dbError(err)
+		}
+
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, dbError(err)
+		}
+		purged += int(n)
+	}
+	return purged, nil
+}
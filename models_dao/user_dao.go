@@ -7,6 +7,9 @@ import (
 	"github.com/jorgini/filmoteka/configs"
 )
 
+// userFields are the columns of filmoteka.User.
+const userFields = "id, login, password, user_role, token_version"
+
 type UserDao struct {
 	db *sqlx.DB
 }
@@ -24,51 +27,91 @@ func (u *UserDao) CreateUser(tx *sqlx.Tx, user filmoteka.User) (int, error) {
 
 	row := tx.QueryRow(query, user.Login, user.Password, user.UserRole)
 	if err := row.Scan(&id); err != nil {
-		return 0, err
+		return 0, dbError(err)
 	}
 	return id, nil
 }
 
-func (u *UserDao) GetUser(login, password string) (filmoteka.User, error) {
-	query := fmt.Sprintf("SELECT * FROM %s WHERE login=$1 AND password=$2", configs.EnvUserTable())
+func (u *UserDao) GetUserByLogin(login string) (filmoteka.User, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE login=$1 AND deleted_at IS NULL", userFields, configs.EnvUserTable())
 
 	var user filmoteka.User
-	if err := u.db.Get(&user, query, login, password); err != nil {
-		return filmoteka.User{}, err
+	if err := u.db.Get(&user, query, login); err != nil {
+		return filmoteka.User{}, dbError(err)
 	}
 	return user, nil
 }
 
+func (u *UserDao) UpdatePassword(tx *sqlx.Tx, id int, password string) error {
+	query := fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2 AND deleted_at IS NULL", configs.EnvUserTable())
+
+	if _, err := tx.Exec(query, password, id); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+// DeleteUserById moves the user to the trash. The tokens of the user stop working, also
+// after a restore.
 func (u *UserDao) DeleteUserById(tx *sqlx.Tx, id int) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", configs.EnvUserTable())
+	query := fmt.Sprintf("UPDATE %s SET deleted_at=now(), token_version=token_version+1 WHERE id=$1 AND deleted_at IS NULL",
+		configs.EnvUserTable())
 
-	if _, err := tx.Query(query, id); err != nil {
-		return err
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.NotFoundError("user not found")
 	}
 	return nil
 }
 
-func (u *UserDao) ValidateUser(id int) (bool, error) {
-	query := fmt.Sprintf("SELECT user_role FROM %s WHERE id=$1", configs.EnvUserTable())
+func (u *UserDao) GetUserRole(id int) (string, error) {
+	query := fmt.Sprintf("SELECT user_role FROM %s WHERE id=$1 AND deleted_at IS NULL", configs.EnvUserTable())
 
 	var userRole string
 	row := u.db.QueryRow(query, id)
 	if err := row.Scan(&userRole); err != nil {
-		return false, err
-	}
-	if userRole == "admin" {
-		return true, nil
-	} else {
-		return false, nil
+		return "", dbError(err)
 	}
+	return userRole, nil
 }
 
 func (u *UserDao) UpdateUser(tx *sqlx.Tx, login, userRole string) error {
-	query := fmt.Sprintf("UPDATE %s SET user_role=$1 WHERE login=$2", configs.EnvUserTable())
+	query := fmt.Sprintf("UPDATE %s SET user_role=$1, token_version=token_version+1 WHERE login=$2 AND deleted_at IS NULL",
+		configs.EnvUserTable())
 
-	_, err := tx.Query(query, userRole, login)
+	result, err := tx.Exec(query, userRole, login)
 	if err != nil {
-		return err
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.NotFoundError("user not found")
+	}
+	return nil
+}
+
+func (u *UserDao) GetTokenVersion(id int) (int, error) {
+	query := fmt.Sprintf("SELECT token_version FROM %s WHERE id=$1 AND deleted_at IS NULL", configs.EnvUserTable())
+
+	var version int
+	row := u.db.QueryRow(query, id)
+	if err := row.Scan(&version); err != nil {
+		return 0, dbError(err)
+	}
+	return version, nil
+}
+
+func (u *UserDao) IncrementTokenVersion(tx *sqlx.Tx, id int) error {
+	query := fmt.Sprintf("UPDATE %s SET token_version=token_version+1 WHERE id=$1", configs.EnvUserTable())
+
+	if _, err := tx.Exec(query, id); err != nil {
+		return dbError(err)
 	}
 	return nil
 }
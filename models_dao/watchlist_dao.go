@@ -0,0 +1,80 @@
+package models_dao
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+)
+
+type WatchlistDao struct {
+	db *sqlx.DB
+}
+
+func NewWatchlistDao(db *sqlx.DB) *WatchlistDao {
+	return &WatchlistDao{
+		db: db,
+	}
+}
+
+func (w *WatchlistDao) AddToWatchlist(tx *sqlx.Tx, entry filmoteka.WatchlistEntry) error {
+	if err := lockLiveFilm(tx, entry.FilmId, shareLock); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, film_id, status, watched_on) 
+		values ($1, $2, $3, TO_DATE($4,'DD-MM-YYYY'))
+		ON CONFLICT (user_id, film_id) DO UPDATE SET status=EXCLUDED.status, watched_on=EXCLUDED.watched_on`,
+		configs.EnvWatchlistTable())
+
+	var watchedOn interface{}
+	if entry.WatchedOn != nil {
+		watchedOn = entry.WatchedOn.String()
+	}
+
+	if _, err := tx.Exec(query, entry.UserId, entry.FilmId, entry.Status, watchedOn); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+func (w *WatchlistDao) RemoveFromWatchlist(tx *sqlx.Tx, userId, filmId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id=$1 AND film_id=$2", configs.EnvWatchlistTable())
+
+	result, err := tx.Exec(query, userId, filmId)
+	if err != nil {
+		return dbError(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError(err)
+	} else if n == 0 {
+		return filmoteka.NotFoundError("film not found in watchlist")
+	}
+	return nil
+}
+
+func (w *WatchlistDao) GetWatchlist(userId int, status string, page, limit int) ([]filmoteka.WatchlistItem, error) {
+	query := fmt.Sprintf(`SELECT %s, w.status, w.watched_on, w.added_at FROM %s w 
+		INNER JOIN %s f ON w.film_id=f.id 
+		WHERE w.user_id=$1 AND w.status=$2 AND %s 
+		ORDER BY w.watched_on DESC NULLS LAST, w.added_at DESC 
+		LIMIT $3 OFFSET $4`,
+		filmFields, configs.EnvWatchlistTable(), configs.EnvFilmTable(), liveFilm)
+
+	var items []filmoteka.WatchlistItem
+	if err := w.db.Select(&items, query, userId, status, limit, limit*(page-1)); err != nil {
+		return nil, dbError(err)
+	}
+	return items, nil
+}
+
+func (w *WatchlistDao) CountWatchlist(userId int, status string) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s w INNER JOIN %s f ON w.film_id=f.id
+		WHERE w.user_id=$1 AND w.status=$2 AND %s`, configs.EnvWatchlistTable(), configs.EnvFilmTable(), liveFilm)
+
+	var count int
+	if err := w.db.Get(&count, query, userId, status); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
@@ -0,0 +1,13 @@
+package filmoteka
+
+// Page is the envelope of a paginated list. Next and Prev are links to the neighbour
+// pages and are empty when there is no such page. Page is left out of a list continued
+// after an item, whose position is unknown.
+type Page[T any] struct {
+	Items    []T    `json:"items"`
+	Page     int    `json:"page,omitempty"`
+	PageSize int    `json:"page_size"`
+	Total    int    `json:"total"`
+	Next     string `json:"next,omitempty"`
+	Prev     string `json:"prev,omitempty"`
+}
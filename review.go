@@ -0,0 +1,73 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type Review struct {
+	Id        int       `json:"id" db:"id"`
+	UserId    int       `json:"user_id" db:"user_id"`
+	FilmId    int       `json:"film_id" db:"film_id"`
+	Rating    int       `json:"rating" db:"rating"`
+	Text      string    `json:"text" db:"text"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (r *Review) UnmarshalJSON(data []byte) error {
+	result := struct {
+		FilmId *int    `json:"film_id"`
+		Rating *int    `json:"rating"`
+		Text   *string `json:"text"`
+	}{}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	if result.FilmId == nil || result.Rating == nil || *result.Rating < 0 || *result.Rating > 10 {
+		return errors.New("invalid state for required field(s)")
+	} else {
+		r.FilmId = *result.FilmId
+		r.Rating = *result.Rating
+		if result.Text != nil {
+			r.Text = *result.Text
+		}
+	}
+	return nil
+}
+
+type UpdateReviewInput struct {
+	Rating *int    `json:"rating"`
+	Text   *string `json:"text"`
+}
+
+func (u *UpdateReviewInput) UnmarshalJSON(data []byte) error {
+	type Result UpdateReviewInput
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	if (result.Rating == nil && result.Text == nil) ||
+		(result.Rating != nil && (*result.Rating < 0 || *result.Rating > 10)) {
+		return errors.New("invalid state for required filed to update review")
+	} else {
+		u.Rating = result.Rating
+		u.Text = result.Text
+	}
+	return nil
+}
+
+// UpdateValues returns the new values of the changed columns of the review.
+func (u *UpdateReviewInput) UpdateValues() map[string]interface{} {
+	values := make(map[string]interface{}, 2)
+	if u.Rating != nil {
+		values["rating"] = *u.Rating
+	}
+	if u.Text != nil {
+		values["text"] = *u.Text
+	}
+	return values
+}
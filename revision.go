@@ -0,0 +1,34 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Revision is a version of a film or an actor. Snapshot keeps the fields of the entity
+// and, for a film, its cast and genres. UserId is null for the first revisions of the
+// entities created before the history was kept.
+type Revision struct {
+	Entity    string          `json:"entity" db:"entity"`
+	EntityId  int             `json:"entity_id" db:"entity_id"`
+	Version   int             `json:"version" db:"version"`
+	UserId    *int            `json:"user_id" db:"user_id"`
+	Snapshot  json.RawMessage `json:"snapshot" db:"snapshot"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// FieldChange is a field differing between two revisions, a missing value is null.
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// RevisionDiff lists the changes of the fields from one revision to another.
+type RevisionDiff struct {
+	Entity   string        `json:"entity"`
+	EntityId int           `json:"entity_id"`
+	From     int           `json:"from"`
+	To       int           `json:"to"`
+	Changes  []FieldChange `json:"changes"`
+}
@@ -6,18 +6,21 @@ import (
 )
 
 type ActorService struct {
+	auditor
 	dao models_dao.Actor
 	tx  models_dao.Transaction
 }
 
-func NewActorService(dao models_dao.Actor, tx models_dao.Transaction) *ActorService {
+func NewActorService(dao models_dao.Actor, audit models_dao.Audit, revision models_dao.Revision,
+	tx models_dao.Transaction) *ActorService {
 	return &ActorService{
-		dao: dao,
-		tx:  tx,
+		auditor: auditor{audit: audit, revision: revision},
+		dao:     dao,
+		tx:      tx,
 	}
 }
 
-func (a *ActorService) CreateActor(actor filmoteka.Actor) (int, error) {
+func (a *ActorService) CreateActor(userId int, actor filmoteka.Actor) (int, error) {
 	transaction, err := a.tx.StartTransaction()
 	if err != nil {
 		return 0, err
@@ -28,19 +31,36 @@ func (a *ActorService) CreateActor(actor filmoteka.Actor) (int, error) {
 	if err != nil {
 		return 0, a.tx.ShutDown(transaction, err)
 	}
+
+	if err = a.record(transaction, userId, filmoteka.AuditCreate, filmoteka.AuditActor, id, nil); err != nil {
+		return 0, a.tx.ShutDown(transaction, err)
+	}
 	return id, a.tx.Commit(transaction)
 }
 
-func (a *ActorService) UpdateActor(actor filmoteka.UpdateActorInput) error {
+func (a *ActorService) UpdateActor(userId int, actor filmoteka.UpdateActorInput) error {
 	transaction, err := a.tx.StartTransaction()
 	if err != nil {
 		return err
 	}
 
+	// the actor in the trash stays as it was deleted, it is changed only after a restore
+	if err = a.dao.LockActor(transaction, *actor.Id); err != nil {
+		return a.tx.ShutDown(transaction, err)
+	}
+
+	before, err := a.snapshot(transaction, filmoteka.AuditActor, *actor.Id)
+	if err != nil {
+		return a.tx.ShutDown(transaction, err)
+	}
+
 	if err = a.dao.UpdateActor(transaction, actor); err != nil {
 		return a.tx.ShutDown(transaction, err)
 	}
 
+	if err = a.record(transaction, userId, filmoteka.AuditUpdate, filmoteka.AuditActor, *actor.Id, before); err != nil {
+		return a.tx.ShutDown(transaction, err)
+	}
 	return a.tx.Commit(transaction)
 }
 
@@ -48,21 +68,23 @@ func (a *ActorService) GetActorId(name, surname string) (int, error) {
 	return a.dao.GetActorId(name, surname)
 }
 
-func (a *ActorService) GetActorsList(page, limit int) ([]filmoteka.ActorListItem, error) {
-	actors, err := a.dao.GetActorsList(page, limit)
+// GetActorsList returns a page of actors with their films and the total number of actors.
+func (a *ActorService) GetActorsList(sort []filmoteka.SortKey, page, limit int) ([]filmoteka.ActorListItem, int, error) {
+	actors, err := a.dao.GetActorsList(sort, page, limit)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	list := make([]filmoteka.ActorListItem, len(actors))
-	for i := range actors {
-		list[i].Actor = actors[i]
-		list[i].Films, err = a.dao.GetFilmsWithCurActor(actors[i].Id)
-		if err != nil {
-			return nil, err
-		}
+	total, err := a.dao.CountActors()
+	if err != nil {
+		return nil, 0, err
 	}
-	return list, nil
+
+	list, err := a.fillActorList(actors)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
 }
 
 func (a *ActorService) GetActorById(id int) (filmoteka.ActorListItem, error) {
@@ -75,35 +97,73 @@ func (a *ActorService) GetActorById(id int) (filmoteka.ActorListItem, error) {
 	if err != nil {
 		return filmoteka.ActorListItem{}, err
 	}
-	return filmoteka.ActorListItem{Actor: actor, Films: films}, nil
+
+	credits, err := a.dao.GetCreditsWithCurActor(actor.Id)
+	if err != nil {
+		return filmoteka.ActorListItem{}, err
+	}
+	return filmoteka.ActorListItem{Actor: actor, Films: films, Credits: credits}, nil
 }
 
-func (a *ActorService) SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.ActorListItem, error) {
-	actors, err := a.dao.SearchActor(page, limit, fragment.Name, fragment.Surname)
+func (a *ActorService) SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.ActorListItem, int, error) {
+	actors, err := a.dao.SearchActor(page, limit, fragment)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	total, err := a.dao.CountSearchActor(fragment)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	list, err := a.fillActorList(actors)
+	if err != nil {
+		return nil, 0, err
 	}
+	return list, total, nil
+}
 
+// fillActorList adds the films to the actors, loading them for the whole page at once.
+func (a *ActorService) fillActorList(actors []filmoteka.Actor) ([]filmoteka.ActorListItem, error) {
 	list := make([]filmoteka.ActorListItem, len(actors))
+	if len(actors) == 0 {
+		return list, nil
+	}
+
+	ids := make([]int, len(actors))
+	for i := range actors {
+		ids[i] = actors[i].Id
+	}
+
+	films, err := a.dao.GetFilmsWithActors(ids)
+	if err != nil {
+		return nil, err
+	}
+
 	for i := range actors {
 		list[i].Actor = actors[i]
-		list[i].Films, err = a.dao.GetFilmsWithCurActor(actors[i].Id)
-		if err != nil {
-			return nil, err
-		}
+		list[i].Films = films[actors[i].Id]
 	}
 	return list, nil
 }
 
-func (a *ActorService) DeleteActorById(id int) error {
+func (a *ActorService) DeleteActorById(userId, id int) error {
 	transaction, err := a.tx.StartTransaction()
 	if err != nil {
 		return err
 	}
 
+	before, err := a.snapshot(transaction, filmoteka.AuditActor, id)
+	if err != nil {
+		return a.tx.ShutDown(transaction, err)
+	}
+
 	if err = a.dao.DeleteActorById(transaction, id); err != nil {
 		return a.tx.ShutDown(transaction, err)
 	}
 
+	if err = a.record(transaction, userId, filmoteka.AuditDelete, filmoteka.AuditActor, id, before); err != nil {
+		return a.tx.ShutDown(transaction, err)
+	}
 	return a.tx.Commit(transaction)
 }
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/jorgini/filmoteka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestActorService_UpdateActor(t *testing.T) {
+	testTable := []struct {
+		name              string
+		live              map[int]bool
+		snapshot          json.RawMessage
+		expectedErr       error
+		expectedActor     *filmoteka.UpdateActorInput
+		expectedCommitted bool
+	}{
+		{
+			name:              "Ok",
+			live:              map[int]bool{10: true},
+			snapshot:          json.RawMessage(`{"id": 10, "name": "Old", "deleted_at": null}`),
+			expectedActor:     &filmoteka.UpdateActorInput{Id: ptr(10), Name: ptr("New")},
+			expectedCommitted: true,
+		},
+		{
+			name:        "Missing Actor",
+			live:        map[int]bool{},
+			expectedErr: filmoteka.NotFoundError("actor not found"),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			catalogue := &memoryCatalogue{live: test.live}
+			audit := &memoryAudit{snapshot: test.snapshot}
+			revisions := &memoryRevisions{}
+			tx := &memoryTransaction{}
+			actors := NewActorService(catalogue, audit, revisions, tx)
+
+			err := actors.UpdateActor(3, filmoteka.UpdateActorInput{Id: ptr(10), Name: ptr("New")})
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedActor, catalogue.actor)
+
+			if !test.expectedCommitted {
+				assert.Equal(t, 0, tx.committed)
+				assert.Empty(t, audit.entries)
+				assert.Empty(t, revisions.added)
+				return
+			}
+			assert.Equal(t, 1, tx.committed)
+			require.Len(t, audit.entries, 1)
+			require.Len(t, revisions.added, 1)
+			assert.Equal(t, 10, revisions.added[0].EntityId)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+)
+
+// revisionEntities are the entities keeping the history of their versions.
+var revisionEntities = map[string]struct{}{filmoteka.AuditFilm: {}, filmoteka.AuditActor: {}}
+
+// auditor writes the changes of the services to the audit log in the transaction of the
+// change, so a change is never committed without its entry. The creates and updates of
+// films and actors add also a revision, revision may be nil for the services of other
+// entities.
+type auditor struct {
+	audit    models_dao.Audit
+	revision models_dao.Revision
+}
+
+// snapshot reads the entity before the change.
+func (a auditor) snapshot(tx *sqlx.Tx, entity string, id int) (json.RawMessage, error) {
+	return a.audit.Snapshot(tx, entity, id)
+}
+
+// record adds the change made by the user with the entity state read before it. An update
+// or a delete of a missing entity changes nothing and is not recorded.
+func (a auditor) record(tx *sqlx.Tx, userId int, action, entity string, id int, before json.RawMessage) error {
+	var after json.RawMessage
+	if action != filmoteka.AuditDelete {
+		var err error
+		if after, err = a.audit.Snapshot(tx, entity, id); err != nil {
+			return err
+		}
+	}
+	if before == nil && after == nil {
+		return nil
+	}
+
+	err := a.audit.AddAuditEntry(tx, filmoteka.AuditEntry{
+		UserId:   userId,
+		Entity:   entity,
+		EntityId: id,
+		Action:   action,
+		Before:   before,
+		After:    after,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, ok := revisionEntities[entity]; !ok || after == nil ||
+		(action != filmoteka.AuditCreate && action != filmoteka.AuditUpdate) {
+		return nil
+	}
+	return a.revision.AddRevision(tx, filmoteka.Revision{
+		Entity:   entity,
+		EntityId: id,
+		UserId:   &userId,
+		Snapshot: after,
+	})
+}
+
+type AuditService struct {
+	dao models_dao.Audit
+}
+
+func NewAuditService(dao models_dao.Audit) *AuditService {
+	return &AuditService{
+		dao: dao,
+	}
+}
+
+// GetAuditLog returns a page of the entries selected by the filter and the number of all of them.
+func (a *AuditService) GetAuditLog(filter filmoteka.AuditFilter, page, limit int) ([]filmoteka.AuditEntry, int, error) {
+	entries, err := a.dao.GetAuditLog(filter, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := a.dao.CountAuditLog(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
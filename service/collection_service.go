@@ -0,0 +1,136 @@
+package service
+
+import (
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+)
+
+type CollectionService struct {
+	dao models_dao.Collection
+	tx  models_dao.Transaction
+}
+
+func NewCollectionService(dao models_dao.Collection, tx models_dao.Transaction) *CollectionService {
+	return &CollectionService{
+		dao: dao,
+		tx:  tx,
+	}
+}
+
+var errCollectionNotFound = filmoteka.NotFoundError("collection not found for current user")
+
+func (c *CollectionService) CreateCollection(collection filmoteka.InputCollection) (int, error) {
+	transaction, err := c.tx.StartTransaction()
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := c.dao.CreateCollection(transaction, collection.Collection)
+	if err != nil {
+		return 0, c.tx.ShutDown(transaction, err)
+	}
+
+	if err = c.dao.SetCollectionFilms(transaction, id, collection.Films...); err != nil {
+		return 0, c.tx.ShutDown(transaction, err)
+	}
+	return id, c.tx.Commit(transaction)
+}
+
+func (c *CollectionService) UpdateCollection(userId int, collection filmoteka.UpdateCollectionInput) error {
+	if err := c.checkOwner(*collection.Id, userId); err != nil {
+		return err
+	}
+
+	transaction, err := c.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if len(collection.UpdateValues()) != 0 {
+		if err = c.dao.UpdateCollection(transaction, collection); err != nil {
+			return c.tx.ShutDown(transaction, err)
+		}
+	}
+
+	if collection.Films != nil {
+		if err = c.dao.SetCollectionFilms(transaction, *collection.Id, *collection.Films...); err != nil {
+			return c.tx.ShutDown(transaction, err)
+		}
+	}
+	return c.tx.Commit(transaction)
+}
+
+// GetCollection returns a collection with its films in their stored order. Private
+// collections are visible to their owner only; userId is 0 for anonymous requests.
+func (c *CollectionService) GetCollection(id, userId int) (filmoteka.CollectionListItem, error) {
+	collection, err := c.dao.GetCollectionById(id)
+	if err != nil {
+		return filmoteka.CollectionListItem{}, err
+	}
+	if !collection.IsPublic && collection.OwnerId != userId {
+		return filmoteka.CollectionListItem{}, errCollectionNotFound
+	}
+
+	films, err := c.dao.GetCollectionFilms(id)
+	if err != nil {
+		return filmoteka.CollectionListItem{}, err
+	}
+	return filmoteka.CollectionListItem{Collection: collection, Films: films}, nil
+}
+
+// GetPublicCollections returns a page of the public collections, the latest first, and the
+// number of all of them.
+func (c *CollectionService) GetPublicCollections(page, limit int) ([]filmoteka.Collection, int, error) {
+	collections, err := c.dao.GetPublicCollections(page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := c.dao.CountPublicCollections()
+	if err != nil {
+		return nil, 0, err
+	}
+	return collections, total, nil
+}
+
+// GetUserCollections returns a page of the collections of the user, the latest first, and
+// the number of all of them.
+func (c *CollectionService) GetUserCollections(userId, page, limit int) ([]filmoteka.Collection, int, error) {
+	collections, err := c.dao.GetUserCollections(userId, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := c.dao.CountUserCollections(userId)
+	if err != nil {
+		return nil, 0, err
+	}
+	return collections, total, nil
+}
+
+func (c *CollectionService) DeleteCollection(id, userId int) error {
+	if err := c.checkOwner(id, userId); err != nil {
+		return err
+	}
+
+	transaction, err := c.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err = c.dao.DeleteCollectionById(transaction, id); err != nil {
+		return c.tx.ShutDown(transaction, err)
+	}
+	return c.tx.Commit(transaction)
+}
+
+func (c *CollectionService) checkOwner(id, userId int) error {
+	collection, err := c.dao.GetCollectionById(id)
+	if err != nil {
+		return err
+	}
+	if collection.OwnerId != userId {
+		return errCollectionNotFound
+	}
+	return nil
+}
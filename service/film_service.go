@@ -1,26 +1,31 @@
 package service
 
 import (
+	"github.com/jmoiron/sqlx"
 	"github.com/jorgini/filmoteka"
 	"github.com/jorgini/filmoteka/models_dao"
-	"github.com/sirupsen/logrus"
 )
 
 type FilmService struct {
 	Actor
-	tx   models_dao.Transaction
-	film models_dao.Film
+	auditor
+	tx    models_dao.Transaction
+	film  models_dao.Film
+	genre models_dao.Genre
 }
 
-func NewFilmService(filmDao models_dao.Film, actorDao models_dao.Actor, tx models_dao.Transaction) *FilmService {
+func NewFilmService(filmDao models_dao.Film, actorDao models_dao.Actor, genreDao models_dao.Genre,
+	auditDao models_dao.Audit, revisionDao models_dao.Revision, tx models_dao.Transaction) *FilmService {
 	return &FilmService{
-		Actor: NewActorService(actorDao, tx),
-		tx:    tx,
-		film:  filmDao,
+		Actor:   NewActorService(actorDao, auditDao, revisionDao, tx),
+		auditor: auditor{audit: auditDao, revision: revisionDao},
+		tx:      tx,
+		film:    filmDao,
+		genre:   genreDao,
 	}
 }
 
-func (f *FilmService) CreateFilm(film filmoteka.InputFilm) (int, error) {
+func (f *FilmService) CreateFilm(userId int, film filmoteka.InputFilm) (int, error) {
 	transaction, err := f.tx.StartTransaction()
 	if err != nil {
 		return 0, err
@@ -31,64 +36,113 @@ func (f *FilmService) CreateFilm(film filmoteka.InputFilm) (int, error) {
 		return 0, f.tx.ShutDown(transaction, err)
 	}
 
-	for i, actor := range film.Cast {
-		actorId, err := f.Actor.GetActorId(actor.Name, actor.Surname)
+	if _, err := f.addCast(transaction, filmId, film.Cast); err != nil {
+		return 0, f.tx.ShutDown(transaction, err)
+	}
+
+	for _, name := range film.Genres {
+		genreId, err := f.genre.GetGenreId(name)
 		if err != nil {
-			return 0, f.tx.ShutDown(transaction, err)
+			return 0, f.tx.ShutDown(transaction, missingReference(err, "genres", name))
 		}
 
-		if err := f.film.AddDependency(transaction, filmId, actorId); err != nil {
-			logrus.Info(i)
+		if err := f.film.AddGenreDependency(transaction, filmId, genreId); err != nil {
 			return 0, f.tx.ShutDown(transaction, err)
 		}
 	}
 
+	if err := f.addCredits(transaction, filmId, film.Credits); err != nil {
+		return 0, f.tx.ShutDown(transaction, err)
+	}
+
+	if err := f.record(transaction, userId, filmoteka.AuditCreate, filmoteka.AuditFilm, filmId, nil); err != nil {
+		return 0, f.tx.ShutDown(transaction, err)
+	}
 	return filmId, f.tx.Commit(transaction)
 }
 
-func (f *FilmService) UpdateFilm(film filmoteka.UpdateFilmInput) error {
+func (f *FilmService) UpdateFilm(userId int, film filmoteka.UpdateFilmInput) error {
 	transaction, err := f.tx.StartTransaction()
 	if err != nil {
 		return err
 	}
 
-	if film.GetValuesUpdate() != "" {
+	// the film in the trash stays as it was deleted, it is changed only after a restore
+	if err := f.film.LockFilm(transaction, *film.Id); err != nil {
+		return f.tx.ShutDown(transaction, err)
+	}
+
+	before, err := f.snapshot(transaction, filmoteka.AuditFilm, *film.Id)
+	if err != nil {
+		return f.tx.ShutDown(transaction, err)
+	}
+
+	if len(film.UpdateValues()) != 0 {
 		if err := f.film.UpdateFilm(transaction, film); err != nil {
 			return f.tx.ShutDown(transaction, err)
 		}
 	}
 
-	actorsIds := make([]int, len(*film.Actors))
-	for i, actor := range *film.Actors {
-		actorId, err := f.Actor.GetActorId(actor.Name, actor.Surname)
+	if film.Actors != nil {
+		actorsIds, err := f.addCast(transaction, *film.Id, *film.Actors)
 		if err != nil {
 			return f.tx.ShutDown(transaction, err)
 		}
-		actorsIds[i] = actorId
+
+		if err := f.film.PruneDependencies(transaction, *film.Id, actorsIds...); err != nil {
+			return f.tx.ShutDown(transaction, err)
+		}
 	}
 
-	if err := f.film.UpdateDependencies(transaction, *film.Id, actorsIds...); err != nil {
-		return f.tx.ShutDown(transaction, err)
+	if film.Genres != nil {
+		genreIds := make([]int, len(*film.Genres))
+		for i, name := range *film.Genres {
+			genreId, err := f.genre.GetGenreId(name)
+			if err != nil {
+				return f.tx.ShutDown(transaction, missingReference(err, "genres", name))
+			}
+			genreIds[i] = genreId
+		}
+
+		if err := f.film.UpdateGenreDependencies(transaction, *film.Id, genreIds...); err != nil {
+			return f.tx.ShutDown(transaction, err)
+		}
 	}
 
+	if film.Credits != nil {
+		if err := f.film.DeleteCredits(transaction, *film.Id); err != nil {
+			return f.tx.ShutDown(transaction, err)
+		}
+
+		if err := f.addCredits(transaction, *film.Id, *film.Credits); err != nil {
+			return f.tx.ShutDown(transaction, err)
+		}
+	}
+
+	if err := f.record(transaction, userId, filmoteka.AuditUpdate, filmoteka.AuditFilm, *film.Id, before); err != nil {
+		return f.tx.ShutDown(transaction, err)
+	}
 	return f.tx.Commit(transaction)
 }
 
-func (f *FilmService) GetSortedFilmList(sortBy string, page, limit int) ([]filmoteka.InputFilm, error) {
-	films, err := f.film.GetSortedFilmList(sortBy, page, limit)
+// GetSortedFilmList returns a page of the sorted films and the number of films in the
+// whole list. A non zero after continues the list right after the film with this id.
+func (f *FilmService) GetSortedFilmList(sort []filmoteka.SortKey, genre *string, after, page, limit int) ([]filmoteka.InputFilm, int, error) {
+	films, err := f.film.GetSortedFilmList(sort, genre, after, page, limit)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	output := make([]filmoteka.InputFilm, len(films))
-	for i := range output {
-		output[i].Film = films[i]
-		output[i].Cast, err = f.film.GetActorsInCurFilm(films[i].Id)
-		if err != nil {
-			return nil, err
-		}
+	total, err := f.film.CountFilms(genre)
+	if err != nil {
+		return nil, 0, err
 	}
-	return output, nil
+
+	list, err := f.fillFilmList(films)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
 }
 
 func (f *FilmService) GetCurFilm(id int) (filmoteka.InputFilm, error) {
@@ -104,52 +158,222 @@ func (f *FilmService) GetCurFilm(id int) (filmoteka.InputFilm, error) {
 		return filmoteka.InputFilm{}, err
 	}
 
+	film.Genres, err = f.film.GetGenresInCurFilm(id)
+	if err != nil {
+		return filmoteka.InputFilm{}, err
+	}
+
+	film.Credits, err = f.film.GetCreditsInCurFilm(id)
+	if err != nil {
+		return filmoteka.InputFilm{}, err
+	}
+
 	return film, nil
 }
 
-func (f *FilmService) GetSearchFilmList(page, limit int, fragment filmoteka.FilmSearchFragment) ([]filmoteka.InputFilm, error) {
+func (f *FilmService) GetFilmCast(id int) (filmoteka.Cast, error) {
+	if _, err := f.film.GetCurFilm(id); err != nil {
+		return nil, err
+	}
+	return f.film.GetActorsInCurFilm(id)
+}
+
+// GetSearchFilmList finds films by substrings of the title or the cast names, or ranks
+// them by full-text search when the fragment has a query.
+func (f *FilmService) GetSearchFilmList(page, limit int, fragment filmoteka.FilmSearchFragment) ([]filmoteka.FilmSearchResult, int, error) {
+	if fragment.Query != nil {
+		return f.fullTextSearch(page, limit, *fragment.Query, fragment.Genre)
+	}
+
+	var films []filmoteka.Film
+	var total int
+	var err error
 	if fragment.Name == nil && fragment.Surname == nil {
-		films, err := f.film.GetFilmListByTitle(page, limit, *fragment.Title)
-		if err != nil {
-			return nil, err
+		var title string
+		if fragment.Title != nil {
+			title = *fragment.Title
 		}
 
-		output := make([]filmoteka.InputFilm, len(films))
-		for i := range films {
-			output[i].Film = films[i]
-			output[i].Cast, err = f.film.GetActorsInCurFilm(films[i].Id)
-			if err != nil {
-				return nil, err
-			}
+		if films, err = f.film.GetFilmListByTitle(page, limit, title, fragment.Genre); err != nil {
+			return nil, 0, err
+		}
+		if total, err = f.film.CountFilmsByTitle(title, fragment.Genre); err != nil {
+			return nil, 0, err
 		}
-		return output, nil
 	} else {
-		films, err := f.film.GetFilmListByActor(page, limit, fragment)
+		if films, err = f.film.GetFilmListByActor(page, limit, fragment); err != nil {
+			return nil, 0, err
+		}
+		if total, err = f.film.CountFilmsByActor(fragment); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	list, err := f.fillFilmList(films)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]filmoteka.FilmSearchResult, len(list))
+	for i := range list {
+		results[i].InputFilm = list[i]
+	}
+	return results, total, nil
+}
+
+// GetFilteredFilmList returns a page of the filtered films with the facet counts of the whole filter.
+func (f *FilmService) GetFilteredFilmList(page, limit int, filter filmoteka.FilmFilter) ([]filmoteka.InputFilm, int, filmoteka.Facets, error) {
+	films, err := f.film.GetFilteredFilmList(page, limit, filter)
+	if err != nil {
+		return nil, 0, filmoteka.Facets{}, err
+	}
+
+	total, err := f.film.CountFilteredFilms(filter)
+	if err != nil {
+		return nil, 0, filmoteka.Facets{}, err
+	}
+
+	facets, err := f.film.GetFilmFacets(filter)
+	if err != nil {
+		return nil, 0, filmoteka.Facets{}, err
+	}
+	if facets.Years == nil {
+		facets.Years = []filmoteka.FacetBucket{}
+	}
+	if facets.Ratings == nil {
+		facets.Ratings = []filmoteka.FacetBucket{}
+	}
+
+	list, err := f.fillFilmList(films)
+	if err != nil {
+		return nil, 0, filmoteka.Facets{}, err
+	}
+	return list, total, facets, nil
+}
+
+func (f *FilmService) fullTextSearch(page, limit int, search string, genre *string) ([]filmoteka.FilmSearchResult, int, error) {
+	results, err := f.film.SearchFilmsFullText(page, limit, search, genre)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := f.film.CountFilmsFullText(search, genre)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	films := make([]filmoteka.Film, len(results))
+	for i := range results {
+		films[i] = results[i].Film
+	}
+
+	list, err := f.fillFilmList(films)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range results {
+		results[i].InputFilm = list[i]
+	}
+	return results, total, nil
+}
+
+// addCast links every cast member to the film and returns their ids. Entries without
+// an explicit billing position are billed in the order they were listed.
+func (f *FilmService) addCast(transaction *sqlx.Tx, filmId int, cast filmoteka.Cast) ([]int, error) {
+	actorsIds := make([]int, len(cast))
+	for i, actor := range cast {
+		actorId, err := f.Actor.GetActorId(actor.Name, actor.Surname)
 		if err != nil {
+			return nil, missingReference(err, "cast", actor.Name+" "+actor.Surname)
+		}
+
+		billing := actor.Billing
+		if billing == 0 {
+			billing = i + 1
+		}
+
+		if err := f.film.AddDependency(transaction, filmId, actorId, actor.Character, billing); err != nil {
 			return nil, err
 		}
+		actorsIds[i] = actorId
+	}
+	return actorsIds, nil
+}
 
-		output := make([]filmoteka.InputFilm, len(films))
-		for i := range films {
-			output[i].Film = films[i]
-			output[i].Cast, err = f.film.GetActorsInCurFilm(films[i].Id)
+func (f *FilmService) addCredits(transaction *sqlx.Tx, filmId int, credits filmoteka.Credits) error {
+	for role, people := range credits {
+		for _, person := range people {
+			actorId, err := f.Actor.GetActorId(person.Name, person.Surname)
 			if err != nil {
-				return nil, err
+				return missingReference(err, "credits."+role, person.Name+" "+person.Surname)
+			}
+
+			if err := f.film.AddCredit(transaction, filmId, actorId, role); err != nil {
+				return err
 			}
 		}
+	}
+	return nil
+}
+
+// missingReference reports a cast member or genre absent from the database as a problem
+// of the request rather than a missing film.
+func missingReference(err error, field, value string) error {
+	if filmoteka.ErrorCodeOf(err) != filmoteka.CodeNotFound {
+		return err
+	}
+	return &filmoteka.Error{Code: filmoteka.CodeValidation, Message: "referenced record not found",
+		Details: map[string]string{field: value + " not found"}, Err: err}
+}
+
+// fillFilmList adds the cast and the genres to the films, loading them for the whole
+// page at once rather than film by film.
+func (f *FilmService) fillFilmList(films []filmoteka.Film) ([]filmoteka.InputFilm, error) {
+	output := make([]filmoteka.InputFilm, len(films))
+	if len(films) == 0 {
 		return output, nil
 	}
+
+	ids := make([]int, len(films))
+	for i := range films {
+		ids[i] = films[i].Id
+	}
+
+	cast, err := f.film.GetActorsInFilms(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	genres, err := f.film.GetGenresInFilms(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range films {
+		output[i].Film = films[i]
+		output[i].Cast = cast[films[i].Id]
+		output[i].Genres = genres[films[i].Id]
+	}
+	return output, nil
 }
 
-func (f *FilmService) DeleteFilmById(id int) error {
+func (f *FilmService) DeleteFilmById(userId, id int) error {
 	transaction, err := f.tx.StartTransaction()
 	if err != nil {
 		return err
 	}
 
+	before, err := f.snapshot(transaction, filmoteka.AuditFilm, id)
+	if err != nil {
+		return f.tx.ShutDown(transaction, err)
+	}
+
 	if err = f.film.DeleteFilmById(transaction, id); err != nil {
 		return f.tx.ShutDown(transaction, err)
 	}
 
+	if err = f.record(transaction, userId, filmoteka.AuditDelete, filmoteka.AuditFilm, id, before); err != nil {
+		return f.tx.ShutDown(transaction, err)
+	}
 	return f.tx.Commit(transaction)
 }
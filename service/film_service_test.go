@@ -0,0 +1,65 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/jorgini/filmoteka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFilmService_UpdateFilm(t *testing.T) {
+	testTable := []struct {
+		name              string
+		liveFilms         map[int]bool
+		snapshot          json.RawMessage
+		expectedErr       error
+		expectedFilm      *filmoteka.UpdateFilmInput
+		expectedCommitted bool
+	}{
+		{
+			name:              "Ok",
+			liveFilms:         map[int]bool{10: true},
+			snapshot:          json.RawMessage(`{"id": 10, "title": "Old", "deleted_at": null}`),
+			expectedFilm:      &filmoteka.UpdateFilmInput{Id: ptr(10), Title: ptr("New")},
+			expectedCommitted: true,
+		},
+		{
+			name:        "Film In Trash",
+			liveFilms:   map[int]bool{},
+			snapshot:    json.RawMessage(`{"id": 10, "title": "Old", "deleted_at": "2024-01-02T03:04:05Z"}`),
+			expectedErr: filmoteka.NotFoundError("film not found"),
+		},
+		{
+			name:        "Missing Film",
+			liveFilms:   map[int]bool{},
+			expectedErr: filmoteka.NotFoundError("film not found"),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			catalogue := &memoryCatalogue{liveFilms: test.liveFilms}
+			audit := &memoryAudit{snapshot: test.snapshot}
+			revisions := &memoryRevisions{}
+			tx := &memoryTransaction{}
+			films := NewFilmService(catalogue, catalogue, catalogue, audit, revisions, tx)
+
+			err := films.UpdateFilm(3, filmoteka.UpdateFilmInput{Id: ptr(10), Title: ptr("New")})
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedFilm, catalogue.film)
+
+			if !test.expectedCommitted {
+				assert.Equal(t, 0, tx.committed)
+				assert.Empty(t, audit.entries)
+				assert.Empty(t, revisions.added)
+				return
+			}
+			assert.Equal(t, 1, tx.committed)
+			require.Len(t, audit.entries, 1)
+			require.Len(t, revisions.added, 1)
+			assert.Equal(t, 10, revisions.added[0].EntityId)
+		})
+	}
+}
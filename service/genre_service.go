@@ -0,0 +1,68 @@
+package service
+
+import (
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+)
+
+type GenreService struct {
+	dao models_dao.Genre
+	tx  models_dao.Transaction
+}
+
+func NewGenreService(dao models_dao.Genre, tx models_dao.Transaction) *GenreService {
+	return &GenreService{
+		dao: dao,
+		tx:  tx,
+	}
+}
+
+func (g *GenreService) CreateGenre(genre filmoteka.Genre) (int, error) {
+	transaction, err := g.tx.StartTransaction()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	id, err = g.dao.CreateGenre(transaction, genre)
+	if err != nil {
+		return 0, g.tx.ShutDown(transaction, err)
+	}
+	return id, g.tx.Commit(transaction)
+}
+
+func (g *GenreService) UpdateGenre(genre filmoteka.Genre) error {
+	transaction, err := g.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err = g.dao.UpdateGenre(transaction, genre); err != nil {
+		return g.tx.ShutDown(transaction, err)
+	}
+	return g.tx.Commit(transaction)
+}
+
+func (g *GenreService) GetGenreId(name string) (int, error) {
+	return g.dao.GetGenreId(name)
+}
+
+func (g *GenreService) GetGenreById(id int) (filmoteka.Genre, error) {
+	return g.dao.GetGenreById(id)
+}
+
+func (g *GenreService) GetGenresList() ([]filmoteka.Genre, error) {
+	return g.dao.GetGenresList()
+}
+
+func (g *GenreService) DeleteGenreById(id int) error {
+	transaction, err := g.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err = g.dao.DeleteGenreById(transaction, id); err != nil {
+		return g.tx.ShutDown(transaction, err)
+	}
+	return g.tx.Commit(transaction)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/configs"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+)
+
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+// KeySet holds every key tokens may be verified with and the one new tokens are signed with.
+// Keeping the previous key in the set while the active one changes lets tokens issued before
+// the rotation live until they expire.
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+func LoadKeySet() (*KeySet, error) {
+	return NewKeySet(configs.EnvSigningKeys(), configs.EnvActiveSigningKey())
+}
+
+// NewKeySet parses keys in the "kid:alg:source" format, see configs.EnvSigningKeys. If active
+// is empty, the first key of the list is used for signing.
+func NewKeySet(spec, active string) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*signingKey)}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid signing key entry: %s", entry)
+		}
+		if _, ok := set.keys[parts[0]]; ok {
+			return nil, fmt.Errorf("duplicate signing key id: %s", parts[0])
+		}
+
+		key, err := newSigningKey(parts[0], parts[1], parts[2])
+		if err != nil {
+			return nil, err
+		}
+
+		set.keys[key.id] = key
+		if set.active == nil && active == "" {
+			set.active = key
+		}
+	}
+
+	if len(set.keys) == 0 {
+		return nil, errors.New("no signing keys configured")
+	}
+	if active != "" {
+		var ok bool
+		if set.active, ok = set.keys[active]; !ok {
+			return nil, fmt.Errorf("active signing key %s not found", active)
+		}
+	}
+	return set, nil
+}
+
+func newSigningKey(id, alg, source string) (*signingKey, error) {
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	key := &signingKey{id: id, method: method}
+	switch method.(type) {
+	case *jwt.SigningMethodHMAC:
+		key.private = []byte(source)
+		key.public = key.private
+		return key, nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodEd25519:
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := method.(*jwt.SigningMethodRSA); ok {
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		key.private, key.public = private, &private.PublicKey
+	} else {
+		private, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		key.private, key.public = private, private.(ed25519.PrivateKey).Public()
+	}
+	return key, nil
+}
+
+func (k *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.method, claims)
+	token.Header["kid"] = k.active.id
+
+	return token.SignedString(k.active.private)
+}
+
+// keyFunc picks the verification key by the kid header and refuses tokens whose algorithm
+// differs from the key's one, so an RSA public key can never be used as an HMAC secret.
+func (k *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("token has no key id")
+	}
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+	return key.public, nil
+}
+
+// JWKS returns the public part of the asymmetric keys, HMAC secrets are never exposed.
+func (k *KeySet) JWKS() filmoteka.JWKS {
+	jwks := filmoteka.JWKS{Keys: make([]filmoteka.JWK, 0, len(k.keys))}
+
+	for _, key := range k.keys {
+		jwk := filmoteka.JWK{Kid: key.id, Use: "sig", Alg: key.method.Alg()}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].Kid < jwks.Keys[j].Kid })
+	return jwks
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jorgini/filmoteka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeKey stores the private key as a PKCS #8 PEM file and returns its path.
+func writeKey(t *testing.T, name string, private interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	return path
+}
+
+func rsaKey(t *testing.T) *rsa.PrivateKey {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return private
+}
+
+func edKey(t *testing.T) ed25519.PrivateKey {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return private
+}
+
+func TestNewKeySet(t *testing.T) {
+	rsaPath := writeKey(t, "rsa.pem", rsaKey(t))
+	edPath := writeKey(t, "ed.pem", edKey(t))
+	missingPath := filepath.Join(t.TempDir(), "missing.pem")
+
+	testTable := []struct {
+		name           string
+		spec           string
+		active         string
+		expectedKids   []string
+		expectedActive string
+		expectedErr    string
+	}{
+		{
+			name:           "HMAC",
+			spec:           "k1:HS256:secret",
+			expectedKids:   []string{"k1"},
+			expectedActive: "k1",
+		},
+		{
+			name:           "First Key Active",
+			spec:           " k1:HS256:secret ; k2:RS256:" + rsaPath + ";k3:EdDSA:" + edPath + ";",
+			expectedKids:   []string{"k1", "k2", "k3"},
+			expectedActive: "k1",
+		},
+		{
+			name:           "Chosen Active",
+			spec:           "k1:HS256:secret;k2:RS256:" + rsaPath,
+			active:         "k2",
+			expectedKids:   []string{"k1", "k2"},
+			expectedActive: "k2",
+		},
+		{
+			name:           "Secret With Colon",
+			spec:           "k1:HS512:sec:ret",
+			expectedKids:   []string{"k1"},
+			expectedActive: "k1",
+		},
+		{
+			name:        "Empty",
+			spec:        " ; ",
+			expectedErr: "no signing keys configured",
+		},
+		{
+			name:        "Missing Source",
+			spec:        "k1:HS256",
+			expectedErr: "invalid signing key entry: k1:HS256",
+		},
+		{
+			name:        "Empty Kid",
+			spec:        ":HS256:secret",
+			expectedErr: "invalid signing key entry: :HS256:secret",
+		},
+		{
+			name:        "Empty Source",
+			spec:        "k1:HS256:",
+			expectedErr: "invalid signing key entry: k1:HS256:",
+		},
+		{
+			name:        "Unknown Alg",
+			spec:        "k1:XS256:secret",
+			expectedErr: "unsupported signing algorithm: XS256",
+		},
+		{
+			name:        "None Alg",
+			spec:        "k1:none:secret",
+			expectedErr: "unsupported signing algorithm: none",
+		},
+		{
+			name:        "Duplicate Kid",
+			spec:        "k1:HS256:secret;k1:RS256:" + rsaPath,
+			expectedErr: "duplicate signing key id: k1",
+		},
+		{
+			name:        "Unknown Active",
+			spec:        "k1:HS256:secret",
+			active:      "k2",
+			expectedErr: "active signing key k2 not found",
+		},
+		{
+			name:        "Missing Key File",
+			spec:        "k1:RS256:" + missingPath,
+			expectedErr: "open " + missingPath + ": no such file or directory",
+		},
+		{
+			name:        "Key Of Other Alg",
+			spec:        "k1:RS256:" + edPath,
+			expectedErr: "key is not a valid RSA private key",
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			set, err := NewKeySet(test.spec, test.active)
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				assert.Nil(t, set)
+				return
+			}
+
+			require.NoError(t, err)
+			kids := make([]string, 0, len(set.keys))
+			for kid := range set.keys {
+				kids = append(kids, kid)
+			}
+			assert.ElementsMatch(t, test.expectedKids, kids)
+			assert.Equal(t, test.expectedActive, set.active.id)
+		})
+	}
+}
+
+func TestKeySet_keyFunc(t *testing.T) {
+	private := rsaKey(t)
+	set, err := NewKeySet("hs:HS256:secret;rs:RS256:"+writeKey(t, "rsa.pem", private), "")
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name        string
+		method      jwt.SigningMethod
+		header      map[string]interface{}
+		expectedKey interface{}
+		expectedErr string
+	}{
+		{
+			name:        "HMAC",
+			method:      jwt.SigningMethodHS256,
+			header:      map[string]interface{}{"kid": "hs"},
+			expectedKey: []byte("secret"),
+		},
+		{
+			name:        "RSA",
+			method:      jwt.SigningMethodRS256,
+			header:      map[string]interface{}{"kid": "rs"},
+			expectedKey: &private.PublicKey,
+		},
+		{
+			name:        "No Kid",
+			method:      jwt.SigningMethodHS256,
+			header:      map[string]interface{}{},
+			expectedErr: "token has no key id",
+		},
+		{
+			name:        "Kid Not A String",
+			method:      jwt.SigningMethodHS256,
+			header:      map[string]interface{}{"kid": 1},
+			expectedErr: "token has no key id",
+		},
+		{
+			name:        "Unknown Kid",
+			method:      jwt.SigningMethodHS256,
+			header:      map[string]interface{}{"kid": "other"},
+			expectedErr: "unknown signing key",
+		},
+		{
+			name:        "HMAC With Public Key",
+			method:      jwt.SigningMethodHS256,
+			header:      map[string]interface{}{"kid": "rs"},
+			expectedErr: "invalid signing method",
+		},
+		{
+			name:        "Other RSA Alg",
+			method:      jwt.SigningMethodRS512,
+			header:      map[string]interface{}{"kid": "rs"},
+			expectedErr: "invalid signing method",
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			key, err := set.keyFunc(&jwt.Token{Method: test.method, Header: test.header})
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				assert.Nil(t, key)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedKey, key)
+		})
+	}
+}
+
+// TestKeySet_keyFunc_publicKeyAsSecret signs a token with the PEM of the public RSA key as an
+// HMAC secret, the classic confusion of algorithms, and expects it to be refused.
+func TestKeySet_keyFunc_publicKeyAsSecret(t *testing.T) {
+	private := rsaKey(t)
+	set, err := NewKeySet("rs:RS256:"+writeKey(t, "rsa.pem", private), "")
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+	public := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{ID: "jti"})
+	token.Header["kid"] = "rs"
+	forged, err := token.SignedString(public)
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(forged, set.keyFunc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid signing method")
+
+	signed, err := set.sign(jwt.RegisteredClaims{ID: "jti"})
+	require.NoError(t, err)
+	_, err = jwt.Parse(signed, set.keyFunc)
+	assert.NoError(t, err)
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	rsaPrivate, edPrivate := rsaKey(t), edKey(t)
+	set, err := NewKeySet("hs:HS256:top-secret;rs:RS256:"+writeKey(t, "rsa.pem", rsaPrivate)+
+		";ed:EdDSA:"+writeKey(t, "ed.pem", edPrivate), "")
+	require.NoError(t, err)
+
+	expected := filmoteka.JWKS{Keys: []filmoteka.JWK{
+		{
+			Kty: "OKP",
+			Kid: "ed",
+			Use: "sig",
+			Alg: "EdDSA",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(edPrivate.Public().(ed25519.PublicKey)),
+		},
+		{
+			Kty: "RSA",
+			Kid: "rs",
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(rsaPrivate.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaPrivate.E)).Bytes()),
+		},
+	}}
+
+	jwks := set.JWKS()
+	assert.Equal(t, expected, jwks)
+
+	body, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "top-secret")
+	assert.NotContains(t, string(body), base64.RawURLEncoding.EncodeToString(rsaPrivate.D.Bytes()))
+	assert.NotContains(t, string(body), base64.RawURLEncoding.EncodeToString(edPrivate.Seed()))
+	for _, key := range jwks.Keys {
+		var fields map[string]interface{}
+		raw, err := json.Marshal(key)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(raw, &fields))
+		for _, private := range []string{"d", "p", "q", "dp", "dq", "qi", "k"} {
+			assert.NotContains(t, fields, private)
+		}
+	}
+	assert.NotContains(t, string(body), `"kid":"hs"`)
+}
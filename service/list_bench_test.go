@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+	"testing"
+)
+
+// memoryRepository keeps a catalogue in memory and counts the queries the services
+// send to it. Methods the list endpoints do not need are left to the embedded interfaces.
+type memoryRepository struct {
+	models_dao.Film
+	models_dao.Actor
+	films   []filmoteka.Film
+	actors  []filmoteka.Actor
+	queries int
+}
+
+func newMemoryRepository(size int) *memoryRepository {
+	repo := &memoryRepository{
+		films:  make([]filmoteka.Film, size),
+		actors: make([]filmoteka.Actor, size),
+	}
+	for i := 0; i < size; i++ {
+		repo.films[i] = filmoteka.Film{Id: i + 1, Title: fmt.Sprintf("film %d", i+1)}
+		repo.actors[i] = filmoteka.Actor{Id: i + 1, Name: "name", Surname: fmt.Sprintf("surname %d", i+1)}
+	}
+	return repo
+}
+
+func paginate[T any](items []T, page, limit int) []T {
+	from := (page - 1) * limit
+	if from >= len(items) {
+		return nil
+	}
+	to := from + limit
+	if to > len(items) {
+		to = len(items)
+	}
+	return items[from:to]
+}
+
+func (m *memoryRepository) GetSortedFilmList(_ []filmoteka.SortKey, _ *string, _, page, limit int) ([]filmoteka.Film, error) {
+	m.queries++
+	return paginate(m.films, page, limit), nil
+}
+
+func (m *memoryRepository) CountFilms(_ *string) (int, error) {
+	m.queries++
+	return len(m.films), nil
+}
+
+func (m *memoryRepository) GetActorsInFilms(filmIds []int) (map[int][]filmoteka.InputActor, error) {
+	m.queries++
+	cast := make(map[int][]filmoteka.InputActor, len(filmIds))
+	for _, id := range filmIds {
+		cast[id] = []filmoteka.InputActor{{Name: "name", Surname: "surname"}}
+	}
+	return cast, nil
+}
+
+func (m *memoryRepository) GetGenresInFilms(filmIds []int) (map[int][]string, error) {
+	m.queries++
+	genres := make(map[int][]string, len(filmIds))
+	for _, id := range filmIds {
+		genres[id] = []string{"drama"}
+	}
+	return genres, nil
+}
+
+func (m *memoryRepository) GetActorsList(_ []filmoteka.SortKey, page, limit int) ([]filmoteka.Actor, error) {
+	m.queries++
+	return paginate(m.actors, page, limit), nil
+}
+
+func (m *memoryRepository) CountActors() (int, error) {
+	m.queries++
+	return len(m.actors), nil
+}
+
+func (m *memoryRepository) GetFilmsWithActors(actorIds []int) (map[int][]filmoteka.Film, error) {
+	m.queries++
+	films := make(map[int][]filmoteka.Film, len(actorIds))
+	for _, id := range actorIds {
+		films[id] = m.films[id-1 : id]
+	}
+	return films, nil
+}
+
+var pageSizes = []int{10, 50, 100}
+
+func BenchmarkFilmService_GetSortedFilmList(b *testing.B) {
+	for _, size := range pageSizes {
+		b.Run(fmt.Sprintf("page_size=%d", size), func(b *testing.B) {
+			repo := newMemoryRepository(1000)
+			films := NewFilmService(repo, repo, nil, nil, nil, nil)
+
+			for i := 0; i < b.N; i++ {
+				if _, _, err := films.GetSortedFilmList([]filmoteka.SortKey{{Column: "avg_rating", Desc: true}}, nil, 0, 1, size); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(repo.queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkActorService_GetActorsList(b *testing.B) {
+	for _, size := range pageSizes {
+		b.Run(fmt.Sprintf("page_size=%d", size), func(b *testing.B) {
+			repo := newMemoryRepository(1000)
+			actors := NewActorService(repo, nil, nil, nil)
+
+			for i := 0; i < b.N; i++ {
+				if _, _, err := actors.GetActorsList([]filmoteka.SortKey{{Column: "surname"}}, 1, size); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(repo.queries)/float64(b.N), "queries/op")
+		})
+	}
+}
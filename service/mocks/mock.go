@@ -10,9 +10,10 @@
 package mock_service
 
 import (
-	"github.com/jorgini/filmoteka"
 	reflect "reflect"
+	time "time"
 
+	filmoteka "github.com/jorgini/filmoteka"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -69,10 +70,10 @@ func (mr *MockUserMockRecorder) DeleteUserById(id any) *gomock.Call {
 }
 
 // GenerateToken mocks base method.
-func (m *MockUser) GenerateToken(login, password string) (string, error) {
+func (m *MockUser) GenerateToken(login, password string) (filmoteka.TokenPair, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenerateToken", login, password)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(filmoteka.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -83,6 +84,63 @@ func (mr *MockUserMockRecorder) GenerateToken(login, password any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockUser)(nil).GenerateToken), login, password)
 }
 
+// GetJWKS mocks base method.
+func (m *MockUser) GetJWKS() filmoteka.JWKS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJWKS")
+	ret0, _ := ret[0].(filmoteka.JWKS)
+	return ret0
+}
+
+// GetJWKS indicates an expected call of GetJWKS.
+func (mr *MockUserMockRecorder) GetJWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJWKS", reflect.TypeOf((*MockUser)(nil).GetJWKS))
+}
+
+// GetUserRole mocks base method.
+func (m *MockUser) GetUserRole(id int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRole", id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRole indicates an expected call of GetUserRole.
+func (mr *MockUserMockRecorder) GetUserRole(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRole", reflect.TypeOf((*MockUser)(nil).GetUserRole), id)
+}
+
+// Logout mocks base method.
+func (m *MockUser) Logout(accessToken, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", accessToken, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserMockRecorder) Logout(accessToken, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUser)(nil).Logout), accessToken, refreshToken)
+}
+
+// LogoutEverywhere mocks base method.
+func (m *MockUser) LogoutEverywhere(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutEverywhere", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutEverywhere indicates an expected call of LogoutEverywhere.
+func (mr *MockUserMockRecorder) LogoutEverywhere(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutEverywhere", reflect.TypeOf((*MockUser)(nil).LogoutEverywhere), id)
+}
+
 // ParseToken mocks base method.
 func (m *MockUser) ParseToken(token string) (int, error) {
 	m.ctrl.T.Helper()
@@ -98,33 +156,33 @@ func (mr *MockUserMockRecorder) ParseToken(token any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockUser)(nil).ParseToken), token)
 }
 
-// UpdateUser mocks base method.
-func (m *MockUser) UpdateUser(login, userRole string) error {
+// RefreshToken mocks base method.
+func (m *MockUser) RefreshToken(refreshToken string) (filmoteka.TokenPair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", login, userRole)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "RefreshToken", refreshToken)
+	ret0, _ := ret[0].(filmoteka.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockUserMockRecorder) UpdateUser(login, userRole any) *gomock.Call {
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUserMockRecorder) RefreshToken(refreshToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUser)(nil).UpdateUser), login, userRole)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUser)(nil).RefreshToken), refreshToken)
 }
 
-// ValidateUser mocks base method.
-func (m *MockUser) ValidateUser(id int) (bool, error) {
+// UpdateUser mocks base method.
+func (m *MockUser) UpdateUser(callerId int, callerRole, login, userRole string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateUser", id)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UpdateUser", callerId, callerRole, login, userRole)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ValidateUser indicates an expected call of ValidateUser.
-func (mr *MockUserMockRecorder) ValidateUser(id any) *gomock.Call {
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserMockRecorder) UpdateUser(callerId, callerRole, login, userRole any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateUser", reflect.TypeOf((*MockUser)(nil).ValidateUser), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUser)(nil).UpdateUser), callerId, callerRole, login, userRole)
 }
 
 // MockActor is a mock of Actor interface.
@@ -151,32 +209,32 @@ func (m *MockActor) EXPECT() *MockActorMockRecorder {
 }
 
 // CreateActor mocks base method.
-func (m *MockActor) CreateActor(actor filmoteka.Actor) (int, error) {
+func (m *MockActor) CreateActor(userId int, actor filmoteka.Actor) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateActor", actor)
+	ret := m.ctrl.Call(m, "CreateActor", userId, actor)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateActor indicates an expected call of CreateActor.
-func (mr *MockActorMockRecorder) CreateActor(actor any) *gomock.Call {
+func (mr *MockActorMockRecorder) CreateActor(userId, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockActor)(nil).CreateActor), actor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockActor)(nil).CreateActor), userId, actor)
 }
 
 // DeleteActorById mocks base method.
-func (m *MockActor) DeleteActorById(id int) error {
+func (m *MockActor) DeleteActorById(userId, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteActorById", id)
+	ret := m.ctrl.Call(m, "DeleteActorById", userId, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteActorById indicates an expected call of DeleteActorById.
-func (mr *MockActorMockRecorder) DeleteActorById(id any) *gomock.Call {
+func (mr *MockActorMockRecorder) DeleteActorById(userId, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorById", reflect.TypeOf((*MockActor)(nil).DeleteActorById), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorById", reflect.TypeOf((*MockActor)(nil).DeleteActorById), userId, id)
 }
 
 // GetActorById mocks base method.
@@ -210,27 +268,29 @@ func (mr *MockActorMockRecorder) GetActorId(name, surname any) *gomock.Call {
 }
 
 // GetActorsList mocks base method.
-func (m *MockActor) GetActorsList(page, limit int) ([]filmoteka.ActorListItem, error) {
+func (m *MockActor) GetActorsList(sort []filmoteka.SortKey, page, limit int) ([]filmoteka.ActorListItem, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorsList", page, limit)
+	ret := m.ctrl.Call(m, "GetActorsList", sort, page, limit)
 	ret0, _ := ret[0].([]filmoteka.ActorListItem)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetActorsList indicates an expected call of GetActorsList.
-func (mr *MockActorMockRecorder) GetActorsList(page, limit any) *gomock.Call {
+func (mr *MockActorMockRecorder) GetActorsList(sort, page, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsList", reflect.TypeOf((*MockActor)(nil).GetActorsList), page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsList", reflect.TypeOf((*MockActor)(nil).GetActorsList), sort, page, limit)
 }
 
 // SearchActor mocks base method.
-func (m *MockActor) SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.ActorListItem, error) {
+func (m *MockActor) SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.ActorListItem, int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SearchActor", page, limit, fragment)
 	ret0, _ := ret[0].([]filmoteka.ActorListItem)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // SearchActor indicates an expected call of SearchActor.
@@ -240,17 +300,617 @@ func (mr *MockActorMockRecorder) SearchActor(page, limit, fragment any) *gomock.
 }
 
 // UpdateActor mocks base method.
-func (m *MockActor) UpdateActor(actor filmoteka.UpdateActorInput) error {
+func (m *MockActor) UpdateActor(userId int, actor filmoteka.UpdateActorInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateActor", actor)
+	ret := m.ctrl.Call(m, "UpdateActor", userId, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateActor indicates an expected call of UpdateActor.
-func (mr *MockActorMockRecorder) UpdateActor(actor any) *gomock.Call {
+func (mr *MockActorMockRecorder) UpdateActor(userId, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockActor)(nil).UpdateActor), userId, actor)
+}
+
+// MockGenre is a mock of Genre interface.
+type MockGenre struct {
+	ctrl     *gomock.Controller
+	recorder *MockGenreMockRecorder
+}
+
+// MockGenreMockRecorder is the mock recorder for MockGenre.
+type MockGenreMockRecorder struct {
+	mock *MockGenre
+}
+
+// NewMockGenre creates a new mock instance.
+func NewMockGenre(ctrl *gomock.Controller) *MockGenre {
+	mock := &MockGenre{ctrl: ctrl}
+	mock.recorder = &MockGenreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGenre) EXPECT() *MockGenreMockRecorder {
+	return m.recorder
+}
+
+// CreateGenre mocks base method.
+func (m *MockGenre) CreateGenre(genre filmoteka.Genre) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGenre", genre)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGenre indicates an expected call of CreateGenre.
+func (mr *MockGenreMockRecorder) CreateGenre(genre any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGenre", reflect.TypeOf((*MockGenre)(nil).CreateGenre), genre)
+}
+
+// DeleteGenreById mocks base method.
+func (m *MockGenre) DeleteGenreById(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGenreById", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGenreById indicates an expected call of DeleteGenreById.
+func (mr *MockGenreMockRecorder) DeleteGenreById(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGenreById", reflect.TypeOf((*MockGenre)(nil).DeleteGenreById), id)
+}
+
+// GetGenreById mocks base method.
+func (m *MockGenre) GetGenreById(id int) (filmoteka.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreById", id)
+	ret0, _ := ret[0].(filmoteka.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreById indicates an expected call of GetGenreById.
+func (mr *MockGenreMockRecorder) GetGenreById(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreById", reflect.TypeOf((*MockGenre)(nil).GetGenreById), id)
+}
+
+// GetGenreId mocks base method.
+func (m *MockGenre) GetGenreId(name string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreId", name)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreId indicates an expected call of GetGenreId.
+func (mr *MockGenreMockRecorder) GetGenreId(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreId", reflect.TypeOf((*MockGenre)(nil).GetGenreId), name)
+}
+
+// GetGenresList mocks base method.
+func (m *MockGenre) GetGenresList() ([]filmoteka.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenresList")
+	ret0, _ := ret[0].([]filmoteka.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenresList indicates an expected call of GetGenresList.
+func (mr *MockGenreMockRecorder) GetGenresList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenresList", reflect.TypeOf((*MockGenre)(nil).GetGenresList))
+}
+
+// UpdateGenre mocks base method.
+func (m *MockGenre) UpdateGenre(genre filmoteka.Genre) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGenre", genre)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGenre indicates an expected call of UpdateGenre.
+func (mr *MockGenreMockRecorder) UpdateGenre(genre any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGenre", reflect.TypeOf((*MockGenre)(nil).UpdateGenre), genre)
+}
+
+// MockReview is a mock of Review interface.
+type MockReview struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewMockRecorder
+}
+
+// MockReviewMockRecorder is the mock recorder for MockReview.
+type MockReviewMockRecorder struct {
+	mock *MockReview
+}
+
+// NewMockReview creates a new mock instance.
+func NewMockReview(ctrl *gomock.Controller) *MockReview {
+	mock := &MockReview{ctrl: ctrl}
+	mock.recorder = &MockReviewMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReview) EXPECT() *MockReviewMockRecorder {
+	return m.recorder
+}
+
+// CreateReview mocks base method.
+func (m *MockReview) CreateReview(review filmoteka.Review) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", review)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockReviewMockRecorder) CreateReview(review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockReview)(nil).CreateReview), review)
+}
+
+// DeleteReview mocks base method.
+func (m *MockReview) DeleteReview(id, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockReviewMockRecorder) DeleteReview(id, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockReview)(nil).DeleteReview), id, userId)
+}
+
+// GetFilmReviews mocks base method.
+func (m *MockReview) GetFilmReviews(filmId, page, limit int) ([]filmoteka.Review, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmReviews", filmId, page, limit)
+	ret0, _ := ret[0].([]filmoteka.Review)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFilmReviews indicates an expected call of GetFilmReviews.
+func (mr *MockReviewMockRecorder) GetFilmReviews(filmId, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmReviews", reflect.TypeOf((*MockReview)(nil).GetFilmReviews), filmId, page, limit)
+}
+
+// ModerateReview mocks base method.
+func (m *MockReview) ModerateReview(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateReview", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModerateReview indicates an expected call of ModerateReview.
+func (mr *MockReviewMockRecorder) ModerateReview(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateReview", reflect.TypeOf((*MockReview)(nil).ModerateReview), id)
+}
+
+// UpdateReview mocks base method.
+func (m *MockReview) UpdateReview(id, userId int, review filmoteka.UpdateReviewInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", id, userId, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockReviewMockRecorder) UpdateReview(id, userId, review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockReview)(nil).UpdateReview), id, userId, review)
+}
+
+// MockWatchlist is a mock of Watchlist interface.
+type MockWatchlist struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchlistMockRecorder
+}
+
+// MockWatchlistMockRecorder is the mock recorder for MockWatchlist.
+type MockWatchlistMockRecorder struct {
+	mock *MockWatchlist
+}
+
+// NewMockWatchlist creates a new mock instance.
+func NewMockWatchlist(ctrl *gomock.Controller) *MockWatchlist {
+	mock := &MockWatchlist{ctrl: ctrl}
+	mock.recorder = &MockWatchlistMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchlist) EXPECT() *MockWatchlistMockRecorder {
+	return m.recorder
+}
+
+// AddToWatchlist mocks base method.
+func (m *MockWatchlist) AddToWatchlist(entry filmoteka.WatchlistEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToWatchlist", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToWatchlist indicates an expected call of AddToWatchlist.
+func (mr *MockWatchlistMockRecorder) AddToWatchlist(entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToWatchlist", reflect.TypeOf((*MockWatchlist)(nil).AddToWatchlist), entry)
+}
+
+// GetWatchlist mocks base method.
+func (m *MockWatchlist) GetWatchlist(userId int, status string, page, limit int) ([]filmoteka.WatchlistItem, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchlist", userId, status, page, limit)
+	ret0, _ := ret[0].([]filmoteka.WatchlistItem)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWatchlist indicates an expected call of GetWatchlist.
+func (mr *MockWatchlistMockRecorder) GetWatchlist(userId, status, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchlist", reflect.TypeOf((*MockWatchlist)(nil).GetWatchlist), userId, status, page, limit)
+}
+
+// RemoveFromWatchlist mocks base method.
+func (m *MockWatchlist) RemoveFromWatchlist(userId, filmId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromWatchlist", userId, filmId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromWatchlist indicates an expected call of RemoveFromWatchlist.
+func (mr *MockWatchlistMockRecorder) RemoveFromWatchlist(userId, filmId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromWatchlist", reflect.TypeOf((*MockWatchlist)(nil).RemoveFromWatchlist), userId, filmId)
+}
+
+// MockCollection is a mock of Collection interface.
+type MockCollection struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionMockRecorder
+}
+
+// MockCollectionMockRecorder is the mock recorder for MockCollection.
+type MockCollectionMockRecorder struct {
+	mock *MockCollection
+}
+
+// NewMockCollection creates a new mock instance.
+func NewMockCollection(ctrl *gomock.Controller) *MockCollection {
+	mock := &MockCollection{ctrl: ctrl}
+	mock.recorder = &MockCollectionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollection) EXPECT() *MockCollectionMockRecorder {
+	return m.recorder
+}
+
+// CreateCollection mocks base method.
+func (m *MockCollection) CreateCollection(collection filmoteka.InputCollection) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", collection)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockCollectionMockRecorder) CreateCollection(collection any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockCollection)(nil).CreateCollection), collection)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollection) DeleteCollection(id, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionMockRecorder) DeleteCollection(id, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollection)(nil).DeleteCollection), id, userId)
+}
+
+// GetCollection mocks base method.
+func (m *MockCollection) GetCollection(id, userId int) (filmoteka.CollectionListItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollection", id, userId)
+	ret0, _ := ret[0].(filmoteka.CollectionListItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollection indicates an expected call of GetCollection.
+func (mr *MockCollectionMockRecorder) GetCollection(id, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollection", reflect.TypeOf((*MockCollection)(nil).GetCollection), id, userId)
+}
+
+// GetPublicCollections mocks base method.
+func (m *MockCollection) GetPublicCollections(page, limit int) ([]filmoteka.Collection, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicCollections", page, limit)
+	ret0, _ := ret[0].([]filmoteka.Collection)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPublicCollections indicates an expected call of GetPublicCollections.
+func (mr *MockCollectionMockRecorder) GetPublicCollections(page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicCollections", reflect.TypeOf((*MockCollection)(nil).GetPublicCollections), page, limit)
+}
+
+// GetUserCollections mocks base method.
+func (m *MockCollection) GetUserCollections(userId, page, limit int) ([]filmoteka.Collection, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCollections", userId, page, limit)
+	ret0, _ := ret[0].([]filmoteka.Collection)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserCollections indicates an expected call of GetUserCollections.
+func (mr *MockCollectionMockRecorder) GetUserCollections(userId, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCollections", reflect.TypeOf((*MockCollection)(nil).GetUserCollections), userId, page, limit)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollection) UpdateCollection(userId int, collection filmoteka.UpdateCollectionInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", userId, collection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionMockRecorder) UpdateCollection(userId, collection any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollection)(nil).UpdateCollection), userId, collection)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// GetAuditLog mocks base method.
+func (m *MockAudit) GetAuditLog(filter filmoteka.AuditFilter, page, limit int) ([]filmoteka.AuditEntry, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", filter, page, limit)
+	ret0, _ := ret[0].([]filmoteka.AuditEntry)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockAuditMockRecorder) GetAuditLog(filter, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockAudit)(nil).GetAuditLog), filter, page, limit)
+}
+
+// MockRevision is a mock of Revision interface.
+type MockRevision struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionMockRecorder
+}
+
+// MockRevisionMockRecorder is the mock recorder for MockRevision.
+type MockRevisionMockRecorder struct {
+	mock *MockRevision
+}
+
+// NewMockRevision creates a new mock instance.
+func NewMockRevision(ctrl *gomock.Controller) *MockRevision {
+	mock := &MockRevision{ctrl: ctrl}
+	mock.recorder = &MockRevisionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevision) EXPECT() *MockRevisionMockRecorder {
+	return m.recorder
+}
+
+// DiffRevisions mocks base method.
+func (m *MockRevision) DiffRevisions(entity string, id, from, to int) (filmoteka.RevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", entity, id, from, to)
+	ret0, _ := ret[0].(filmoteka.RevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockRevisionMockRecorder) DiffRevisions(entity, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockRevision)(nil).DiffRevisions), entity, id, from, to)
+}
+
+// GetRevisions mocks base method.
+func (m *MockRevision) GetRevisions(entity string, id, page, limit int) ([]filmoteka.Revision, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", entity, id, page, limit)
+	ret0, _ := ret[0].([]filmoteka.Revision)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockRevisionMockRecorder) GetRevisions(entity, id, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockRevision)(nil).GetRevisions), entity, id, page, limit)
+}
+
+// Rollback mocks base method.
+func (m *MockRevision) Rollback(userId int, entity string, id, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", userId, entity, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockRevisionMockRecorder) Rollback(userId, entity, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockRevision)(nil).Rollback), userId, entity, id, version)
+}
+
+// MockTrash is a mock of Trash interface.
+type MockTrash struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashMockRecorder
+}
+
+// MockTrashMockRecorder is the mock recorder for MockTrash.
+type MockTrashMockRecorder struct {
+	mock *MockTrash
+}
+
+// NewMockTrash creates a new mock instance.
+func NewMockTrash(ctrl *gomock.Controller) *MockTrash {
+	mock := &MockTrash{ctrl: ctrl}
+	mock.recorder = &MockTrashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrash) EXPECT() *MockTrashMockRecorder {
+	return m.recorder
+}
+
+// GetDeleted mocks base method.
+func (m *MockTrash) GetDeleted(entity *string, page, limit int) ([]filmoteka.DeletedEntity, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted", entity, page, limit)
+	ret0, _ := ret[0].([]filmoteka.DeletedEntity)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockTrashMockRecorder) GetDeleted(entity, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockTrash)(nil).GetDeleted), entity, page, limit)
+}
+
+// Purge mocks base method.
+func (m *MockTrash) Purge(retention time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", retention)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashMockRecorder) Purge(retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrash)(nil).Purge), retention)
+}
+
+// Restore mocks base method.
+func (m *MockTrash) Restore(userId int, entity string, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", userId, entity, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTrashMockRecorder) Restore(userId, entity, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTrash)(nil).Restore), userId, entity, id)
+}
+
+// MockSuggestion is a mock of Suggestion interface.
+type MockSuggestion struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuggestionMockRecorder
+}
+
+// MockSuggestionMockRecorder is the mock recorder for MockSuggestion.
+type MockSuggestionMockRecorder struct {
+	mock *MockSuggestion
+}
+
+// NewMockSuggestion creates a new mock instance.
+func NewMockSuggestion(ctrl *gomock.Controller) *MockSuggestion {
+	mock := &MockSuggestion{ctrl: ctrl}
+	mock.recorder = &MockSuggestionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuggestion) EXPECT() *MockSuggestionMockRecorder {
+	return m.recorder
+}
+
+// GetSuggestions mocks base method.
+func (m *MockSuggestion) GetSuggestions(prefix string, limit int) ([]filmoteka.Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuggestions", prefix, limit)
+	ret0, _ := ret[0].([]filmoteka.Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuggestions indicates an expected call of GetSuggestions.
+func (mr *MockSuggestionMockRecorder) GetSuggestions(prefix, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockActor)(nil).UpdateActor), actor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuggestions", reflect.TypeOf((*MockSuggestion)(nil).GetSuggestions), prefix, limit)
 }
 
 // MockFilm is a mock of Film interface.
@@ -277,61 +937,61 @@ func (m *MockFilm) EXPECT() *MockFilmMockRecorder {
 }
 
 // CreateActor mocks base method.
-func (m *MockFilm) CreateActor(actor filmoteka.Actor) (int, error) {
+func (m *MockFilm) CreateActor(userId int, actor filmoteka.Actor) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateActor", actor)
+	ret := m.ctrl.Call(m, "CreateActor", userId, actor)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateActor indicates an expected call of CreateActor.
-func (mr *MockFilmMockRecorder) CreateActor(actor any) *gomock.Call {
+func (mr *MockFilmMockRecorder) CreateActor(userId, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockFilm)(nil).CreateActor), actor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockFilm)(nil).CreateActor), userId, actor)
 }
 
 // CreateFilm mocks base method.
-func (m *MockFilm) CreateFilm(film filmoteka.InputFilm) (int, error) {
+func (m *MockFilm) CreateFilm(userId int, film filmoteka.InputFilm) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateFilm", film)
+	ret := m.ctrl.Call(m, "CreateFilm", userId, film)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateFilm indicates an expected call of CreateFilm.
-func (mr *MockFilmMockRecorder) CreateFilm(film any) *gomock.Call {
+func (mr *MockFilmMockRecorder) CreateFilm(userId, film any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFilm", reflect.TypeOf((*MockFilm)(nil).CreateFilm), film)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFilm", reflect.TypeOf((*MockFilm)(nil).CreateFilm), userId, film)
 }
 
 // DeleteActorById mocks base method.
-func (m *MockFilm) DeleteActorById(id int) error {
+func (m *MockFilm) DeleteActorById(userId, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteActorById", id)
+	ret := m.ctrl.Call(m, "DeleteActorById", userId, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteActorById indicates an expected call of DeleteActorById.
-func (mr *MockFilmMockRecorder) DeleteActorById(id any) *gomock.Call {
+func (mr *MockFilmMockRecorder) DeleteActorById(userId, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorById", reflect.TypeOf((*MockFilm)(nil).DeleteActorById), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorById", reflect.TypeOf((*MockFilm)(nil).DeleteActorById), userId, id)
 }
 
 // DeleteFilmById mocks base method.
-func (m *MockFilm) DeleteFilmById(id int) error {
+func (m *MockFilm) DeleteFilmById(userId, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteFilmById", id)
+	ret := m.ctrl.Call(m, "DeleteFilmById", userId, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteFilmById indicates an expected call of DeleteFilmById.
-func (mr *MockFilmMockRecorder) DeleteFilmById(id any) *gomock.Call {
+func (mr *MockFilmMockRecorder) DeleteFilmById(userId, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFilmById", reflect.TypeOf((*MockFilm)(nil).DeleteFilmById), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFilmById", reflect.TypeOf((*MockFilm)(nil).DeleteFilmById), userId, id)
 }
 
 // GetActorById mocks base method.
@@ -365,18 +1025,19 @@ func (mr *MockFilmMockRecorder) GetActorId(name, surname any) *gomock.Call {
 }
 
 // GetActorsList mocks base method.
-func (m *MockFilm) GetActorsList(page, limit int) ([]filmoteka.ActorListItem, error) {
+func (m *MockFilm) GetActorsList(sort []filmoteka.SortKey, page, limit int) ([]filmoteka.ActorListItem, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorsList", page, limit)
+	ret := m.ctrl.Call(m, "GetActorsList", sort, page, limit)
 	ret0, _ := ret[0].([]filmoteka.ActorListItem)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetActorsList indicates an expected call of GetActorsList.
-func (mr *MockFilmMockRecorder) GetActorsList(page, limit any) *gomock.Call {
+func (mr *MockFilmMockRecorder) GetActorsList(sort, page, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsList", reflect.TypeOf((*MockFilm)(nil).GetActorsList), page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsList", reflect.TypeOf((*MockFilm)(nil).GetActorsList), sort, page, limit)
 }
 
 // GetCurFilm mocks base method.
@@ -394,15 +1055,48 @@ func (mr *MockFilmMockRecorder) GetCurFilm(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurFilm", reflect.TypeOf((*MockFilm)(nil).GetCurFilm), id)
 }
 
-// GetSearchFilmList mocks base method.
-func (m *MockFilm) GetSearchFilmList(page, limit int, fragment filmoteka.FilmSearchFragment) ([]filmoteka.InputFilm, error) {
+// GetFilmCast mocks base method.
+func (m *MockFilm) GetFilmCast(id int) (filmoteka.Cast, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSearchFilmList", page, limit, fragment)
-	ret0, _ := ret[0].([]filmoteka.InputFilm)
+	ret := m.ctrl.Call(m, "GetFilmCast", id)
+	ret0, _ := ret[0].(filmoteka.Cast)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetFilmCast indicates an expected call of GetFilmCast.
+func (mr *MockFilmMockRecorder) GetFilmCast(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmCast", reflect.TypeOf((*MockFilm)(nil).GetFilmCast), id)
+}
+
+// GetFilteredFilmList mocks base method.
+func (m *MockFilm) GetFilteredFilmList(page, limit int, filter filmoteka.FilmFilter) ([]filmoteka.InputFilm, int, filmoteka.Facets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilteredFilmList", page, limit, filter)
+	ret0, _ := ret[0].([]filmoteka.InputFilm)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(filmoteka.Facets)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetFilteredFilmList indicates an expected call of GetFilteredFilmList.
+func (mr *MockFilmMockRecorder) GetFilteredFilmList(page, limit, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilteredFilmList", reflect.TypeOf((*MockFilm)(nil).GetFilteredFilmList), page, limit, filter)
+}
+
+// GetSearchFilmList mocks base method.
+func (m *MockFilm) GetSearchFilmList(page, limit int, fragment filmoteka.FilmSearchFragment) ([]filmoteka.FilmSearchResult, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSearchFilmList", page, limit, fragment)
+	ret0, _ := ret[0].([]filmoteka.FilmSearchResult)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // GetSearchFilmList indicates an expected call of GetSearchFilmList.
 func (mr *MockFilmMockRecorder) GetSearchFilmList(page, limit, fragment any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
@@ -410,27 +1104,29 @@ func (mr *MockFilmMockRecorder) GetSearchFilmList(page, limit, fragment any) *go
 }
 
 // GetSortedFilmList mocks base method.
-func (m *MockFilm) GetSortedFilmList(sortBy string, page, limit int) ([]filmoteka.InputFilm, error) {
+func (m *MockFilm) GetSortedFilmList(sort []filmoteka.SortKey, genre *string, after, page, limit int) ([]filmoteka.InputFilm, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSortedFilmList", sortBy, page, limit)
+	ret := m.ctrl.Call(m, "GetSortedFilmList", sort, genre, after, page, limit)
 	ret0, _ := ret[0].([]filmoteka.InputFilm)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetSortedFilmList indicates an expected call of GetSortedFilmList.
-func (mr *MockFilmMockRecorder) GetSortedFilmList(sortBy, page, limit any) *gomock.Call {
+func (mr *MockFilmMockRecorder) GetSortedFilmList(sort, genre, after, page, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSortedFilmList", reflect.TypeOf((*MockFilm)(nil).GetSortedFilmList), sortBy, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSortedFilmList", reflect.TypeOf((*MockFilm)(nil).GetSortedFilmList), sort, genre, after, page, limit)
 }
 
 // SearchActor mocks base method.
-func (m *MockFilm) SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.ActorListItem, error) {
+func (m *MockFilm) SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.ActorListItem, int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SearchActor", page, limit, fragment)
 	ret0, _ := ret[0].([]filmoteka.ActorListItem)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // SearchActor indicates an expected call of SearchActor.
@@ -440,29 +1136,29 @@ func (mr *MockFilmMockRecorder) SearchActor(page, limit, fragment any) *gomock.C
 }
 
 // UpdateActor mocks base method.
-func (m *MockFilm) UpdateActor(actor filmoteka.UpdateActorInput) error {
+func (m *MockFilm) UpdateActor(userId int, actor filmoteka.UpdateActorInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateActor", actor)
+	ret := m.ctrl.Call(m, "UpdateActor", userId, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateActor indicates an expected call of UpdateActor.
-func (mr *MockFilmMockRecorder) UpdateActor(actor any) *gomock.Call {
+func (mr *MockFilmMockRecorder) UpdateActor(userId, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockFilm)(nil).UpdateActor), actor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockFilm)(nil).UpdateActor), userId, actor)
 }
 
 // UpdateFilm mocks base method.
-func (m *MockFilm) UpdateFilm(film filmoteka.UpdateFilmInput) error {
+func (m *MockFilm) UpdateFilm(userId int, film filmoteka.UpdateFilmInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateFilm", film)
+	ret := m.ctrl.Call(m, "UpdateFilm", userId, film)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateFilm indicates an expected call of UpdateFilm.
-func (mr *MockFilmMockRecorder) UpdateFilm(film any) *gomock.Call {
+func (mr *MockFilmMockRecorder) UpdateFilm(userId, film any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFilm", reflect.TypeOf((*MockFilm)(nil).UpdateFilm), film)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFilm", reflect.TypeOf((*MockFilm)(nil).UpdateFilm), userId, film)
 }
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+)
+
+type ReviewService struct {
+	dao models_dao.Review
+	tx  models_dao.Transaction
+}
+
+func NewReviewService(dao models_dao.Review, tx models_dao.Transaction) *ReviewService {
+	return &ReviewService{
+		dao: dao,
+		tx:  tx,
+	}
+}
+
+var errReviewNotFound = filmoteka.NotFoundError("review not found for current user")
+
+func (r *ReviewService) CreateReview(review filmoteka.Review) (int, error) {
+	transaction, err := r.tx.StartTransaction()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	id, err = r.dao.CreateReview(transaction, review)
+	if err != nil {
+		return 0, r.tx.ShutDown(transaction, err)
+	}
+
+	if err = r.dao.RecalculateFilmRating(transaction, review.FilmId); err != nil {
+		return 0, r.tx.ShutDown(transaction, err)
+	}
+	return id, r.tx.Commit(transaction)
+}
+
+func (r *ReviewService) UpdateReview(id, userId int, review filmoteka.UpdateReviewInput) error {
+	transaction, err := r.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	filmId, err := r.dao.UpdateReview(transaction, id, userId, review)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.tx.ShutDown(transaction, errReviewNotFound)
+	} else if err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+
+	if err = r.dao.RecalculateFilmRating(transaction, filmId); err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+	return r.tx.Commit(transaction)
+}
+
+// GetFilmReviews returns a page of the reviews of the film, the latest first, and the
+// number of all of them.
+func (r *ReviewService) GetFilmReviews(filmId, page, limit int) ([]filmoteka.Review, int, error) {
+	reviews, err := r.dao.GetFilmReviews(filmId, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.dao.CountFilmReviews(filmId)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *ReviewService) DeleteReview(id, userId int) error {
+	transaction, err := r.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	filmId, err := r.dao.DeleteReview(transaction, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.tx.ShutDown(transaction, errReviewNotFound)
+	} else if err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+
+	if err = r.dao.RecalculateFilmRating(transaction, filmId); err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+	return r.tx.Commit(transaction)
+}
+
+// ModerateReview removes a review regardless of its author.
+func (r *ReviewService) ModerateReview(id int) error {
+	transaction, err := r.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	filmId, err := r.dao.DeleteReviewById(transaction, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.tx.ShutDown(transaction, filmoteka.NotFoundError("review not found"))
+	} else if err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+
+	if err = r.dao.RecalculateFilmRating(transaction, filmId); err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+	return r.tx.Commit(transaction)
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// snapshotDate is the layout of the dates in the JSON snapshots of the database.
+const snapshotDate = "2006-01-02"
+
+type RevisionService struct {
+	auditor
+	film  models_dao.Film
+	actor models_dao.Actor
+	genre models_dao.Genre
+	tx    models_dao.Transaction
+}
+
+func NewRevisionService(revisionDao models_dao.Revision, filmDao models_dao.Film, actorDao models_dao.Actor,
+	genreDao models_dao.Genre, auditDao models_dao.Audit, tx models_dao.Transaction) *RevisionService {
+	return &RevisionService{
+		auditor: auditor{audit: auditDao, revision: revisionDao},
+		film:    filmDao,
+		actor:   actorDao,
+		genre:   genreDao,
+		tx:      tx,
+	}
+}
+
+// filmRevision is the snapshot of a film, the cast refers to the actors by id.
+type filmRevision struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	IssueDate   string  `json:"issue_date"`
+	Rating      int     `json:"rating"`
+	Cast        []struct {
+		ActorId   int    `json:"actor_id"`
+		Character string `json:"character"`
+		Billing   int    `json:"billing"`
+	} `json:"cast"`
+	Genres []string `json:"genres"`
+}
+
+type actorRevision struct {
+	Name     string `json:"name"`
+	Surname  string `json:"surname"`
+	Sex      string `json:"sex"`
+	Birthday string `json:"birthday"`
+}
+
+// GetRevisions returns a page of the revisions of the entity, the latest first, and the
+// number of all of them.
+func (r *RevisionService) GetRevisions(entity string, id, page, limit int) ([]filmoteka.Revision, int, error) {
+	revisions, err := r.revision.GetRevisions(entity, id, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.revision.CountRevisions(entity, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if revisions == nil {
+		revisions = []filmoteka.Revision{}
+	}
+	return revisions, total, nil
+}
+
+// DiffRevisions compares the snapshots of two revisions of the entity field by field.
+func (r *RevisionService) DiffRevisions(entity string, id, from, to int) (filmoteka.RevisionDiff, error) {
+	before, err := r.revision.GetRevision(entity, id, from)
+	if err != nil {
+		return filmoteka.RevisionDiff{}, err
+	}
+
+	after, err := r.revision.GetRevision(entity, id, to)
+	if err != nil {
+		return filmoteka.RevisionDiff{}, err
+	}
+
+	changes, err := diffSnapshots(before.Snapshot, after.Snapshot)
+	if err != nil {
+		return filmoteka.RevisionDiff{}, err
+	}
+	return filmoteka.RevisionDiff{Entity: entity, EntityId: id, From: from, To: to, Changes: changes}, nil
+}
+
+// Rollback brings the entity back to the state of the revision on behalf of the user. The
+// rollback is an update of the entity, so it is recorded as a new revision.
+func (r *RevisionService) Rollback(userId int, entity string, id, version int) error {
+	revision, err := r.revision.GetRevision(entity, id, version)
+	if err != nil {
+		return err
+	}
+
+	transaction, err := r.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err = r.lockLive(transaction, entity, id); err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+
+	before, err := r.snapshot(transaction, entity, id)
+	if err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+
+	if entity == filmoteka.AuditFilm {
+		err = r.rollbackFilm(transaction, id, revision.Snapshot)
+	} else {
+		err = r.rollbackActor(transaction, id, revision.Snapshot)
+	}
+	if err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+
+	if err = r.record(transaction, userId, filmoteka.AuditUpdate, entity, id, before); err != nil {
+		return r.tx.ShutDown(transaction, err)
+	}
+	return r.tx.Commit(transaction)
+}
+
+// lockLive reports an entity missing or in the trash as not found, it has to be restored
+// before a rollback.
+func (r *RevisionService) lockLive(transaction *sqlx.Tx, entity string, id int) error {
+	switch entity {
+	case filmoteka.AuditFilm:
+		return r.film.LockFilm(transaction, id)
+	case filmoteka.AuditActor:
+		return r.actor.LockActor(transaction, id)
+	}
+	return filmoteka.ValidationError("unknown entity", map[string]string{"entity": entity})
+}
+
+func (r *RevisionService) rollbackFilm(transaction *sqlx.Tx, id int, snapshot json.RawMessage) error {
+	var film filmRevision
+	if err := json.Unmarshal(snapshot, &film); err != nil {
+		return err
+	}
+
+	issued, err := time.Parse(snapshotDate, film.IssueDate)
+	if err != nil {
+		return err
+	}
+	date := filmoteka.Date(issued)
+
+	// the links to the actors in the trash are not brought back, they have to be restored first
+	for _, member := range film.Cast {
+		if _, err := r.actor.GetActorById(member.ActorId); filmoteka.ErrorCodeOf(err) == filmoteka.CodeNotFound {
+			return filmoteka.ValidationError("cast of the revision refers to a deleted actor",
+				map[string]string{"cast": fmt.Sprintf("actor %d not found, restore it before the rollback", member.ActorId)})
+		} else if err != nil {
+			return err
+		}
+	}
+
+	input := filmoteka.UpdateFilmInput{Id: &id, Title: &film.Title, Description: film.Description,
+		IssueDate: &date, Rating: &film.Rating}
+	if err := r.film.UpdateFilm(transaction, input); err != nil {
+		return err
+	}
+
+	actorIds := make([]int, len(film.Cast))
+	for i, member := range film.Cast {
+		if err := r.film.AddDependency(transaction, id, member.ActorId, member.Character, member.Billing); err != nil {
+			return err
+		}
+		actorIds[i] = member.ActorId
+	}
+	if err := r.film.PruneDependencies(transaction, id, actorIds...); err != nil {
+		return err
+	}
+
+	genreIds := make([]int, len(film.Genres))
+	for i, name := range film.Genres {
+		genreId, err := r.genre.GetGenreId(name)
+		if err != nil {
+			return missingReference(err, "genres", name)
+		}
+		genreIds[i] = genreId
+	}
+	return r.film.UpdateGenreDependencies(transaction, id, genreIds...)
+}
+
+func (r *RevisionService) rollbackActor(transaction *sqlx.Tx, id int, snapshot json.RawMessage) error {
+	var actor actorRevision
+	if err := json.Unmarshal(snapshot, &actor); err != nil {
+		return err
+	}
+
+	born, err := time.Parse(snapshotDate, actor.Birthday)
+	if err != nil {
+		return err
+	}
+	birthday := filmoteka.Date(born)
+
+	return r.actor.UpdateActor(transaction, filmoteka.UpdateActorInput{Id: &id, Name: &actor.Name,
+		Surname: &actor.Surname, Sex: &actor.Sex, Birthday: &birthday})
+}
+
+// diffSnapshots lists the fields whose values differ between the snapshots, in the order
+// of their names.
+func diffSnapshots(from, to json.RawMessage) ([]filmoteka.FieldChange, error) {
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(from, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &after); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []filmoteka.FieldChange{}
+	for _, field := range fields {
+		same, err := sameJSON(before[field], after[field])
+		if err != nil {
+			return nil, err
+		}
+		if !same {
+			changes = append(changes, filmoteka.FieldChange{Field: field, From: before[field], To: after[field]})
+		}
+	}
+	return changes, nil
+}
+
+// sameJSON compares the values rather than their text, a missing value is null.
+func sameJSON(a, b json.RawMessage) (bool, error) {
+	var x, y interface{}
+	if a != nil {
+		if err := json.Unmarshal(a, &x); err != nil {
+			return false, err
+		}
+	}
+	if b != nil {
+		if err := json.Unmarshal(b, &y); err != nil {
+			return false, err
+		}
+	}
+	return reflect.DeepEqual(x, y), nil
+}
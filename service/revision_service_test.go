@@ -0,0 +1,339 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// memoryRevisions keeps the revisions of a single entity and the ones added by the service.
+type memoryRevisions struct {
+	models_dao.Revision
+	revisions map[int]filmoteka.Revision
+	added     []filmoteka.Revision
+}
+
+func (m *memoryRevisions) GetRevision(_ string, _, version int) (filmoteka.Revision, error) {
+	revision, ok := m.revisions[version]
+	if !ok {
+		return filmoteka.Revision{}, filmoteka.NotFoundError("requested record not found")
+	}
+	return revision, nil
+}
+
+func (m *memoryRevisions) AddRevision(_ *sqlx.Tx, revision filmoteka.Revision) error {
+	m.added = append(m.added, revision)
+	return nil
+}
+
+// memoryAudit returns the same snapshot of the entity before and after the change.
+type memoryAudit struct {
+	models_dao.Audit
+	snapshot json.RawMessage
+	entries  []filmoteka.AuditEntry
+}
+
+func (m *memoryAudit) Snapshot(_ *sqlx.Tx, _ string, _ int) (json.RawMessage, error) {
+	return m.snapshot, nil
+}
+
+func (m *memoryAudit) AddAuditEntry(_ *sqlx.Tx, entry filmoteka.AuditEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+// memoryCatalogue records the writes of a rollback or an update, only the films in liveFilms
+// and the actors in live are not in the trash.
+type memoryCatalogue struct {
+	models_dao.Film
+	models_dao.Actor
+	models_dao.Genre
+	liveFilms    map[int]bool
+	live         map[int]bool
+	genres       map[string]int
+	film         *filmoteka.UpdateFilmInput
+	actor        *filmoteka.UpdateActorInput
+	dependencies []int
+	pruned       []int
+	genreIds     []int
+}
+
+func (m *memoryCatalogue) LockFilm(_ *sqlx.Tx, id int) error {
+	if !m.liveFilms[id] {
+		return filmoteka.NotFoundError("film not found")
+	}
+	return nil
+}
+
+func (m *memoryCatalogue) UpdateFilm(_ *sqlx.Tx, film filmoteka.UpdateFilmInput) error {
+	m.film = &film
+	return nil
+}
+
+func (m *memoryCatalogue) AddDependency(_ *sqlx.Tx, _, actorId int, _ string, _ int) error {
+	m.dependencies = append(m.dependencies, actorId)
+	return nil
+}
+
+func (m *memoryCatalogue) PruneDependencies(_ *sqlx.Tx, _ int, actorIds ...int) error {
+	m.pruned = actorIds
+	return nil
+}
+
+func (m *memoryCatalogue) UpdateGenreDependencies(_ *sqlx.Tx, _ int, genreIds ...int) error {
+	m.genreIds = genreIds
+	return nil
+}
+
+func (m *memoryCatalogue) GetGenreId(name string) (int, error) {
+	id, ok := m.genres[name]
+	if !ok {
+		return 0, filmoteka.NotFoundError("requested record not found")
+	}
+	return id, nil
+}
+
+func (m *memoryCatalogue) GetActorById(id int) (filmoteka.Actor, error) {
+	if !m.live[id] {
+		return filmoteka.Actor{}, filmoteka.NotFoundError("requested record not found")
+	}
+	return filmoteka.Actor{Id: id}, nil
+}
+
+func (m *memoryCatalogue) LockActor(_ *sqlx.Tx, id int) error {
+	if !m.live[id] {
+		return filmoteka.NotFoundError("actor not found")
+	}
+	return nil
+}
+
+func (m *memoryCatalogue) UpdateActor(_ *sqlx.Tx, actor filmoteka.UpdateActorInput) error {
+	m.actor = &actor
+	return nil
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func date(t *testing.T, value string) *filmoteka.Date {
+	parsed, err := time.Parse(snapshotDate, value)
+	require.NoError(t, err)
+	return ptr(filmoteka.Date(parsed))
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	testTable := []struct {
+		name            string
+		from            string
+		to              string
+		expectedChanges []filmoteka.FieldChange
+		expectedErr     bool
+	}{
+		{
+			name:            "Same",
+			from:            `{"title": "Film", "rating": 7}`,
+			to:              `{"rating":7,"title":"Film"}`,
+			expectedChanges: []filmoteka.FieldChange{},
+		},
+		{
+			name:            "Same Values In Other Notation",
+			from:            `{"rating": 7, "cast": [{"actor_id": 1, "billing": 1}]}`,
+			to:              `{"rating": 7.0, "cast": [{"billing": 1, "actor_id": 1}]}`,
+			expectedChanges: []filmoteka.FieldChange{},
+		},
+		{
+			name: "Changed In Order Of Names",
+			from: `{"title": "Old", "rating": 7, "genres": ["drama"]}`,
+			to:   `{"title": "New", "rating": 8, "genres": ["drama"]}`,
+			expectedChanges: []filmoteka.FieldChange{
+				{Field: "rating", From: json.RawMessage(`7`), To: json.RawMessage(`8`)},
+				{Field: "title", From: json.RawMessage(`"Old"`), To: json.RawMessage(`"New"`)},
+			},
+		},
+		{
+			name: "Order Of Array",
+			from: `{"genres": ["drama", "comedy"]}`,
+			to:   `{"genres": ["comedy", "drama"]}`,
+			expectedChanges: []filmoteka.FieldChange{
+				{Field: "genres", From: json.RawMessage(`["drama", "comedy"]`), To: json.RawMessage(`["comedy", "drama"]`)},
+			},
+		},
+		{
+			name: "Added And Removed",
+			from: `{"description": "text"}`,
+			to:   `{"sex": "male"}`,
+			expectedChanges: []filmoteka.FieldChange{
+				{Field: "description", From: json.RawMessage(`"text"`)},
+				{Field: "sex", To: json.RawMessage(`"male"`)},
+			},
+		},
+		{
+			name:            "Missing Is Null",
+			from:            `{"description": null}`,
+			to:              `{}`,
+			expectedChanges: []filmoteka.FieldChange{},
+		},
+		{
+			name:        "Invalid From",
+			from:        `[1, 2]`,
+			to:          `{}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Invalid To",
+			from:        `{}`,
+			to:          `{"title": `,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			changes, err := diffSnapshots(json.RawMessage(test.from), json.RawMessage(test.to))
+
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedChanges, changes)
+		})
+	}
+}
+
+func TestRevisionService_Rollback(t *testing.T) {
+	filmSnapshot := json.RawMessage(`{"title": "Old", "description": null, "issue_date": "2001-02-03", "rating": 7,
+		"cast": [{"actor_id": 1, "character": "Hero", "billing": 1}, {"actor_id": 2, "character": "Villain", "billing": 2}],
+		"genres": ["drama"]}`)
+	actorSnapshot := json.RawMessage(`{"name": "Name", "surname": "Surname", "sex": "female", "birthday": "1980-05-06"}`)
+
+	testTable := []struct {
+		name              string
+		entity            string
+		version           int
+		revision          json.RawMessage
+		current           json.RawMessage
+		liveFilms         map[int]bool
+		live              map[int]bool
+		expectedErr       error
+		expectedFilm      *filmoteka.UpdateFilmInput
+		expectedActor     *filmoteka.UpdateActorInput
+		expectedCast      []int
+		expectedGenres    []int
+		expectedCommitted bool
+	}{
+		{
+			name:      "Film",
+			entity:    filmoteka.AuditFilm,
+			version:   1,
+			revision:  filmSnapshot,
+			current:   json.RawMessage(`{"id": 10, "title": "New", "deleted_at": null}`),
+			liveFilms: map[int]bool{10: true},
+			live:      map[int]bool{1: true, 2: true},
+			expectedFilm: &filmoteka.UpdateFilmInput{Id: ptr(10), Title: ptr("Old"), IssueDate: date(t, "2001-02-03"),
+				Rating: ptr(7)},
+			expectedCast:      []int{1, 2},
+			expectedGenres:    []int{5},
+			expectedCommitted: true,
+		},
+		{
+			name:      "Film With Deleted Actor",
+			entity:    filmoteka.AuditFilm,
+			version:   1,
+			revision:  filmSnapshot,
+			current:   json.RawMessage(`{"id": 10, "title": "New", "deleted_at": null}`),
+			liveFilms: map[int]bool{10: true},
+			live:      map[int]bool{1: true},
+			expectedErr: filmoteka.ValidationError("cast of the revision refers to a deleted actor",
+				map[string]string{"cast": "actor 2 not found, restore it before the rollback"}),
+		},
+		{
+			name:      "Film With Deleted Genre",
+			entity:    filmoteka.AuditFilm,
+			version:   1,
+			revision:  json.RawMessage(`{"title": "Old", "issue_date": "2001-02-03", "rating": 7, "genres": ["horror"]}`),
+			current:   json.RawMessage(`{"id": 10, "title": "New", "deleted_at": null}`),
+			liveFilms: map[int]bool{10: true},
+			expectedFilm: &filmoteka.UpdateFilmInput{Id: ptr(10), Title: ptr("Old"), IssueDate: date(t, "2001-02-03"),
+				Rating: ptr(7)},
+			expectedErr: &filmoteka.Error{Code: filmoteka.CodeValidation, Message: "referenced record not found",
+				Details: map[string]string{"genres": "horror not found"},
+				Err:     filmoteka.NotFoundError("requested record not found")},
+		},
+		{
+			name:     "Actor",
+			entity:   filmoteka.AuditActor,
+			version:  1,
+			revision: actorSnapshot,
+			current:  json.RawMessage(`{"id": 10, "name": "Other", "deleted_at": null}`),
+			live:     map[int]bool{10: true},
+			expectedActor: &filmoteka.UpdateActorInput{Id: ptr(10), Name: ptr("Name"), Surname: ptr("Surname"),
+				Sex: ptr("female"), Birthday: date(t, "1980-05-06")},
+			expectedCommitted: true,
+		},
+		{
+			name:        "Film In Trash",
+			entity:      filmoteka.AuditFilm,
+			version:     1,
+			revision:    filmSnapshot,
+			current:     json.RawMessage(`{"id": 10, "title": "New", "deleted_at": "2024-01-02T03:04:05Z"}`),
+			live:        map[int]bool{1: true, 2: true},
+			expectedErr: filmoteka.NotFoundError("film not found"),
+		},
+		{
+			name:        "Actor In Trash",
+			entity:      filmoteka.AuditActor,
+			version:     1,
+			revision:    actorSnapshot,
+			current:     json.RawMessage(`{"id": 10, "name": "Other", "deleted_at": "2024-01-02T03:04:05Z"}`),
+			expectedErr: filmoteka.NotFoundError("actor not found"),
+		},
+		{
+			name:        "Missing Revision",
+			entity:      filmoteka.AuditActor,
+			version:     2,
+			revision:    actorSnapshot,
+			current:     json.RawMessage(`{"id": 10, "name": "Other", "deleted_at": null}`),
+			expectedErr: filmoteka.NotFoundError("requested record not found"),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			revisions := &memoryRevisions{revisions: map[int]filmoteka.Revision{
+				1: {Entity: test.entity, EntityId: 10, Version: 1, Snapshot: test.revision},
+			}}
+			audit := &memoryAudit{snapshot: test.current}
+			catalogue := &memoryCatalogue{liveFilms: test.liveFilms, live: test.live, genres: map[string]int{"drama": 5}}
+			tx := &memoryTransaction{}
+			service := NewRevisionService(revisions, catalogue, catalogue, catalogue, audit, tx)
+
+			err := service.Rollback(3, test.entity, 10, test.version)
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedFilm, catalogue.film)
+			assert.Equal(t, test.expectedActor, catalogue.actor)
+			assert.Equal(t, test.expectedCast, catalogue.dependencies)
+			assert.Equal(t, test.expectedGenres, catalogue.genreIds)
+
+			if !test.expectedCommitted {
+				assert.Equal(t, 0, tx.committed)
+				assert.Empty(t, revisions.added)
+				return
+			}
+			assert.Equal(t, 1, tx.committed)
+			assert.Equal(t, test.expectedCast, catalogue.pruned)
+			require.Len(t, audit.entries, 1)
+			assert.Equal(t, filmoteka.AuditEntry{UserId: 3, Entity: test.entity, EntityId: 10,
+				Action: filmoteka.AuditUpdate, Before: test.current, After: test.current}, audit.entries[0])
+			require.Len(t, revisions.added, 1)
+			assert.Equal(t, 10, revisions.added[0].EntityId)
+		})
+	}
+}
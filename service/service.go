@@ -3,49 +3,124 @@ package service
 import (
 	"github.com/jorgini/filmoteka"
 	"github.com/jorgini/filmoteka/models_dao"
+	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 
 type User interface {
 	CreateUser(user filmoteka.User) (int, error)
-	GenerateToken(login, password string) (string, error)
+	GenerateToken(login, password string) (filmoteka.TokenPair, error)
+	RefreshToken(refreshToken string) (filmoteka.TokenPair, error)
 	ParseToken(token string) (int, error)
+	Logout(accessToken, refreshToken string) error
+	LogoutEverywhere(id int) error
+	GetJWKS() filmoteka.JWKS
 	DeleteUserById(id int) error
-	ValidateUser(id int) (bool, error)
-	UpdateUser(login, userRole string) error
+	GetUserRole(id int) (string, error)
+	UpdateUser(callerId int, callerRole, login, userRole string) error
 }
 
 type Actor interface {
-	CreateActor(actor filmoteka.Actor) (int, error)
-	UpdateActor(actor filmoteka.UpdateActorInput) error
+	CreateActor(userId int, actor filmoteka.Actor) (int, error)
+	UpdateActor(userId int, actor filmoteka.UpdateActorInput) error
 	GetActorById(id int) (filmoteka.ActorListItem, error)
 	GetActorId(name, surname string) (int, error)
-	GetActorsList(page, limit int) ([]filmoteka.ActorListItem, error)
-	SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.ActorListItem, error)
-	DeleteActorById(id int) error
+	GetActorsList(sort []filmoteka.SortKey, page, limit int) ([]filmoteka.ActorListItem, int, error)
+	SearchActor(page, limit int, fragment filmoteka.ActorSearchFragment) ([]filmoteka.ActorListItem, int, error)
+	DeleteActorById(userId, id int) error
+}
+
+type Genre interface {
+	CreateGenre(genre filmoteka.Genre) (int, error)
+	UpdateGenre(genre filmoteka.Genre) error
+	GetGenreId(name string) (int, error)
+	GetGenreById(id int) (filmoteka.Genre, error)
+	GetGenresList() ([]filmoteka.Genre, error)
+	DeleteGenreById(id int) error
+}
+
+type Review interface {
+	CreateReview(review filmoteka.Review) (int, error)
+	UpdateReview(id, userId int, review filmoteka.UpdateReviewInput) error
+	GetFilmReviews(filmId, page, limit int) ([]filmoteka.Review, int, error)
+	DeleteReview(id, userId int) error
+	ModerateReview(id int) error
+}
+
+type Watchlist interface {
+	AddToWatchlist(entry filmoteka.WatchlistEntry) error
+	RemoveFromWatchlist(userId, filmId int) error
+	GetWatchlist(userId int, status string, page, limit int) ([]filmoteka.WatchlistItem, int, error)
+}
+
+type Collection interface {
+	CreateCollection(collection filmoteka.InputCollection) (int, error)
+	UpdateCollection(userId int, collection filmoteka.UpdateCollectionInput) error
+	GetCollection(id, userId int) (filmoteka.CollectionListItem, error)
+	GetPublicCollections(page, limit int) ([]filmoteka.Collection, int, error)
+	GetUserCollections(userId, page, limit int) ([]filmoteka.Collection, int, error)
+	DeleteCollection(id, userId int) error
+}
+
+type Audit interface {
+	GetAuditLog(filter filmoteka.AuditFilter, page, limit int) ([]filmoteka.AuditEntry, int, error)
+}
+
+type Revision interface {
+	GetRevisions(entity string, id, page, limit int) ([]filmoteka.Revision, int, error)
+	DiffRevisions(entity string, id, from, to int) (filmoteka.RevisionDiff, error)
+	Rollback(userId int, entity string, id, version int) error
+}
+
+type Trash interface {
+	GetDeleted(entity *string, page, limit int) ([]filmoteka.DeletedEntity, int, error)
+	Restore(userId int, entity string, id int) error
+	Purge(retention time.Duration) (int, error)
+}
+
+type Suggestion interface {
+	GetSuggestions(prefix string, limit int) ([]filmoteka.Suggestion, error)
 }
 
 type Film interface {
 	Actor
-	CreateFilm(film filmoteka.InputFilm) (int, error)
-	UpdateFilm(film filmoteka.UpdateFilmInput) error
-	GetSortedFilmList(sortBy string, page, limit int) ([]filmoteka.InputFilm, error)
+	CreateFilm(userId int, film filmoteka.InputFilm) (int, error)
+	UpdateFilm(userId int, film filmoteka.UpdateFilmInput) error
+	GetSortedFilmList(sort []filmoteka.SortKey, genre *string, after, page, limit int) ([]filmoteka.InputFilm, int, error)
 	GetCurFilm(id int) (filmoteka.InputFilm, error)
-	GetSearchFilmList(page, limit int, fragment filmoteka.FilmSearchFragment) ([]filmoteka.InputFilm, error)
-	DeleteFilmById(id int) error
+	GetFilmCast(id int) (filmoteka.Cast, error)
+	GetSearchFilmList(page, limit int, fragment filmoteka.FilmSearchFragment) ([]filmoteka.FilmSearchResult, int, error)
+	GetFilteredFilmList(page, limit int, filter filmoteka.FilmFilter) ([]filmoteka.InputFilm, int, filmoteka.Facets, error)
+	DeleteFilmById(userId, id int) error
 }
 
 type Service struct {
 	User
 	Actor
 	Film
+	Genre
+	Review
+	Watchlist
+	Collection
+	Suggestion
+	Audit
+	Revision
+	Trash
 }
 
-func NewService(dao *models_dao.Repository) *Service {
+func NewService(dao *models_dao.Repository, keys *KeySet) *Service {
 	return &Service{
-		User:  NewUserService(dao.User, dao.Transaction),
-		Actor: NewActorService(dao.Actor, dao.Transaction),
-		Film:  NewFilmService(dao.Film, dao.Actor, dao.Transaction),
+		User:       NewUserService(dao.User, dao.Token, dao.Audit, keys, dao.Transaction),
+		Actor:      NewActorService(dao.Actor, dao.Audit, dao.Revision, dao.Transaction),
+		Film:       NewFilmService(dao.Film, dao.Actor, dao.Genre, dao.Audit, dao.Revision, dao.Transaction),
+		Genre:      NewGenreService(dao.Genre, dao.Transaction),
+		Review:     NewReviewService(dao.Review, dao.Transaction),
+		Watchlist:  NewWatchlistService(dao.Watchlist, dao.Transaction),
+		Collection: NewCollectionService(dao.Collection, dao.Transaction),
+		Suggestion: NewSuggestionService(dao.Suggestion),
+		Audit:      NewAuditService(dao.Audit),
+		Revision:   NewRevisionService(dao.Revision, dao.Film, dao.Actor, dao.Genre, dao.Audit, dao.Transaction),
+		Trash:      NewTrashService(dao.Trash, dao.Audit, dao.Transaction),
 	}
 }
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+)
+
+type SuggestionService struct {
+	dao models_dao.Suggestion
+}
+
+func NewSuggestionService(dao models_dao.Suggestion) *SuggestionService {
+	return &SuggestionService{
+		dao: dao,
+	}
+}
+
+// GetSuggestions returns up to limit films and actors starting with the prefix, the best first.
+func (s *SuggestionService) GetSuggestions(prefix string, limit int) ([]filmoteka.Suggestion, error) {
+	suggestions, err := s.dao.GetSuggestions(prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	if suggestions == nil {
+		suggestions = []filmoteka.Suggestion{}
+	}
+	return suggestions, nil
+}
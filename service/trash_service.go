@@ -0,0 +1,78 @@
+package service
+
+import (
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+	"time"
+)
+
+type TrashService struct {
+	auditor
+	dao models_dao.Trash
+	tx  models_dao.Transaction
+}
+
+func NewTrashService(dao models_dao.Trash, audit models_dao.Audit, tx models_dao.Transaction) *TrashService {
+	return &TrashService{
+		auditor: auditor{audit: audit},
+		dao:     dao,
+		tx:      tx,
+	}
+}
+
+// GetDeleted returns a page of the deleted entities of the kind, all kinds for a nil
+// entity, and the number of all of them.
+func (t *TrashService) GetDeleted(entity *string, page, limit int) ([]filmoteka.DeletedEntity, int, error) {
+	deleted, err := t.dao.GetDeleted(entity, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := t.dao.CountDeleted(entity)
+	if err != nil {
+		return nil, 0, err
+	}
+	if deleted == nil {
+		deleted = []filmoteka.DeletedEntity{}
+	}
+	return deleted, total, nil
+}
+
+// Restore takes the entity out of the trash on behalf of the user. The links of the
+// entity were kept in the trash, so a film comes back with its cast, genres and credits.
+func (t *TrashService) Restore(userId int, entity string, id int) error {
+	transaction, err := t.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	before, err := t.snapshot(transaction, entity, id)
+	if err != nil {
+		return t.tx.ShutDown(transaction, err)
+	}
+
+	if err = t.dao.Restore(transaction, entity, id); err != nil {
+		return t.tx.ShutDown(transaction, err)
+	}
+
+	if err = t.record(transaction, userId, filmoteka.AuditRestore, entity, id, before); err != nil {
+		return t.tx.ShutDown(transaction, err)
+	}
+	return t.tx.Commit(transaction)
+}
+
+// Purge removes for good the entities deleted longer than retention ago and returns how
+// many of them were removed. The purge is not a change of a user and is not audited, the
+// earlier entries of the purged entities stay in the log.
+func (t *TrashService) Purge(retention time.Duration) (int, error) {
+	transaction, err := t.tx.StartTransaction()
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := t.dao.Purge(transaction, time.Now().Add(-retention))
+	if err != nil {
+		return 0, t.tx.ShutDown(transaction, err)
+	}
+	return purged, t.tx.Commit(transaction)
+}
@@ -1,40 +1,77 @@
 package service
 
 import (
+	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jmoiron/sqlx"
 	"github.com/jorgini/filmoteka"
 	"github.com/jorgini/filmoteka/models_dao"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
 	"time"
 )
 
 const (
-	salt     = "foiewnjgin2jr34fnvwi0"
-	signKey  = "cwenin314cI#knewvm349,wnd2"
-	tokenTTL = 12 * time.Hour
+	// legacySalt was used by the former SHA-1 scheme, it is kept only to verify
+	// passwords that have not been rehashed with bcrypt yet.
+	legacySalt      = "foiewnjgin2jr34fnvwi0"
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+	hashCost        = bcrypt.DefaultCost
 )
 
+var (
+	errInvalidCredentials  = filmoteka.UnauthorizedError("invalid login or password")
+	errInvalidRefreshToken = filmoteka.UnauthorizedError("invalid refresh token")
+	errTokenRevoked        = filmoteka.UnauthorizedError("token has been revoked")
+	errAdminOnly           = filmoteka.ForbiddenError("only admin can grant or revoke the admin role")
+)
+
+// tokenClaims carries the version of the user's tokens at the moment of issue, so
+// raising the version in the database invalidates every token issued before.
 type tokenClaims struct {
 	jwt.RegisteredClaims
-	UserId int `json:"user_id"`
+	UserId  int `json:"user_id"`
+	Version int `json:"ver"`
 }
 
 type UserService struct {
-	dao models_dao.User
-	tx  models_dao.Transaction
+	auditor
+	dao    models_dao.User
+	tokens models_dao.Token
+	keys   *KeySet
+	tx     models_dao.Transaction
 }
 
-func NewUserService(dao models_dao.User, tx models_dao.Transaction) *UserService {
+func NewUserService(dao models_dao.User, tokens models_dao.Token, audit models_dao.Audit, keys *KeySet,
+	tx models_dao.Transaction) *UserService {
 	return &UserService{
-		dao: dao,
-		tx:  tx,
+		auditor: auditor{audit: audit},
+		dao:     dao,
+		tokens:  tokens,
+		keys:    keys,
+		tx:      tx,
 	}
 }
 
+// CreateUser registers a regular user, the roles are granted only by UpdateUser.
 func (u *UserService) CreateUser(user filmoteka.User) (int, error) {
-	user.Password = generateHashPassword(user.Password)
+	user.UserRole = filmoteka.RegularRole
+
+	var err error
+	user.Password, err = generateHashPassword(user.Password)
+	if err != nil {
+		return 0, err
+	}
 
 	transaction, err := u.tx.StartTransaction()
 	if err != nil {
@@ -47,78 +84,351 @@ func (u *UserService) CreateUser(user filmoteka.User) (int, error) {
 		return 0, u.tx.ShutDown(transaction, err)
 	}
 
+	// the registered user is the author of the own account
+	if err = u.record(transaction, id, filmoteka.AuditCreate, filmoteka.AuditUser, id, nil); err != nil {
+		return 0, u.tx.ShutDown(transaction, err)
+	}
 	return id, u.tx.Commit(transaction)
 }
 
-func (u *UserService) GenerateToken(login, password string) (string, error) {
-	user, err := u.dao.GetUser(login, generateHashPassword(password))
+func (u *UserService) GenerateToken(login, password string) (filmoteka.TokenPair, error) {
+	user, err := u.dao.GetUserByLogin(login)
+	if filmoteka.ErrorCodeOf(err) == filmoteka.CodeNotFound {
+		return filmoteka.TokenPair{}, errInvalidCredentials
+	} else if err != nil {
+		return filmoteka.TokenPair{}, err
+	}
+
+	rehash, err := verifyPassword(user.Password, password)
 	if err != nil {
-		return "", err
+		return filmoteka.TokenPair{}, err
+	}
+	// the upgrade of the hash is best-effort, the password has been verified already
+	if rehash {
+		if err = u.rehashPassword(user.Id, password); err != nil {
+			logrus.Warnf("rehash of the password of the user %d failed with: %s", user.Id, err.Error())
+		}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
-		UserId: user.Id,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenTTL)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	})
+	transaction, err := u.tx.StartTransaction()
+	if err != nil {
+		return filmoteka.TokenPair{}, err
+	}
 
-	return token.SignedString([]byte(signKey))
+	tokens, err := u.issueTokens(transaction, user.Id, user.TokenVersion)
+	if err != nil {
+		return filmoteka.TokenPair{}, u.tx.ShutDown(transaction, err)
+	}
+	return tokens, u.tx.Commit(transaction)
 }
 
-func (u *UserService) ParseToken(accessToken string) (int, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
+// RefreshToken exchanges a refresh token for a new token pair. Every refresh token
+// can be used only once: presenting an already rotated one means it has leaked, so
+// all refresh tokens of its owner are revoked.
+func (u *UserService) RefreshToken(refreshToken string) (filmoteka.TokenPair, error) {
+	stored, err := u.tokens.GetRefreshToken(hashToken(refreshToken))
+	if filmoteka.ErrorCodeOf(err) == filmoteka.CodeNotFound {
+		return filmoteka.TokenPair{}, errInvalidRefreshToken
+	} else if err != nil {
+		return filmoteka.TokenPair{}, err
+	}
+	if !stored.Revoked && stored.ExpiresAt.Before(time.Now()) {
+		return filmoteka.TokenPair{}, errInvalidRefreshToken
+	}
+
+	transaction, err := u.tx.StartTransaction()
+	if err != nil {
+		return filmoteka.TokenPair{}, err
+	}
+
+	if stored.Revoked {
+		if err := u.tokens.RevokeUserRefreshTokens(transaction, stored.UserId); err != nil {
+			return filmoteka.TokenPair{}, u.tx.ShutDown(transaction, err)
 		}
+		if err := u.tx.Commit(transaction); err != nil {
+			return filmoteka.TokenPair{}, err
+		}
+		return filmoteka.TokenPair{}, errInvalidRefreshToken
+	}
 
-		return []byte(signKey), nil
-	})
+	// the token of a deleted user is as invalid as an unknown one
+	version, err := u.dao.GetTokenVersion(stored.UserId)
+	if filmoteka.ErrorCodeOf(err) == filmoteka.CodeNotFound {
+		return filmoteka.TokenPair{}, u.tx.ShutDown(transaction, errInvalidRefreshToken)
+	} else if err != nil {
+		return filmoteka.TokenPair{}, u.tx.ShutDown(transaction, err)
+	}
+
+	if err := u.tokens.RevokeRefreshToken(transaction, stored.Id); err != nil {
+		return filmoteka.TokenPair{}, u.tx.ShutDown(transaction, err)
+	}
+
+	tokens, err := u.issueTokens(transaction, stored.UserId, version)
+	if err != nil {
+		return filmoteka.TokenPair{}, u.tx.ShutDown(transaction, err)
+	}
+	return tokens, u.tx.Commit(transaction)
+}
 
+func (u *UserService) ParseToken(accessToken string) (int, error) {
+	claims, err := u.parseClaims(accessToken)
 	if err != nil {
 		return 0, err
 	}
 
-	claims, ok := token.Claims.(*tokenClaims)
-	if !ok {
-		return 0, errors.New("token claims are not of type *tokenClaims")
+	if revoked, err := u.tokens.IsAccessTokenRevoked(claims.ID); err != nil {
+		return 0, err
+	} else if revoked {
+		return 0, errTokenRevoked
+	}
+
+	version, err := u.dao.GetTokenVersion(claims.UserId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, errTokenRevoked
+	} else if err != nil {
+		return 0, err
+	}
+	if version != claims.Version {
+		return 0, errTokenRevoked
 	}
 
 	return claims.UserId, nil
 }
 
-func (u *UserService) ValidateUser(id int) (bool, error) {
-	return u.dao.ValidateUser(id)
+// Logout revokes the given access token and, if passed, the refresh token of the same user.
+func (u *UserService) Logout(accessToken, refreshToken string) error {
+	claims, err := u.parseClaims(accessToken)
+	if err != nil {
+		return err
+	}
+
+	transaction, err := u.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err := u.tokens.RevokeAccessToken(transaction, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return u.tx.ShutDown(transaction, err)
+	}
+
+	if refreshToken != "" {
+		stored, err := u.tokens.GetRefreshToken(hashToken(refreshToken))
+		if filmoteka.ErrorCodeOf(err) == filmoteka.CodeNotFound {
+			return u.tx.ShutDown(transaction, errInvalidRefreshToken)
+		} else if err != nil {
+			return u.tx.ShutDown(transaction, err)
+		}
+		if stored.UserId != claims.UserId {
+			return u.tx.ShutDown(transaction, errInvalidRefreshToken)
+		}
+
+		if !stored.Revoked {
+			if err := u.tokens.RevokeRefreshToken(transaction, stored.Id); err != nil {
+				return u.tx.ShutDown(transaction, err)
+			}
+		}
+	}
+
+	return u.tx.Commit(transaction)
+}
+
+func (u *UserService) LogoutEverywhere(id int) error {
+	transaction, err := u.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err := u.dao.IncrementTokenVersion(transaction, id); err != nil {
+		return u.tx.ShutDown(transaction, err)
+	}
+
+	if err := u.tokens.RevokeUserRefreshTokens(transaction, id); err != nil {
+		return u.tx.ShutDown(transaction, err)
+	}
+	return u.tx.Commit(transaction)
+}
+
+func (u *UserService) GetJWKS() filmoteka.JWKS {
+	return u.keys.JWKS()
+}
+
+func (u *UserService) GetUserRole(id int) (string, error) {
+	return u.dao.GetUserRole(id)
 }
 
-func (u *UserService) UpdateUser(login, userRole string) error {
+// UpdateUser changes the role of the user with the given login. Only admins may grant
+// the admin role or change the role of another admin.
+func (u *UserService) UpdateUser(callerId int, callerRole, login, userRole string) error {
+	if callerRole != filmoteka.AdminRole && userRole == filmoteka.AdminRole {
+		return errAdminOnly
+	}
+
+	user, err := u.dao.GetUserByLogin(login)
+	if err != nil {
+		return err
+	}
+	if callerRole != filmoteka.AdminRole && user.UserRole == filmoteka.AdminRole {
+		return errAdminOnly
+	}
+
 	transaction, err := u.tx.StartTransaction()
 	if err != nil {
 		return err
 	}
 
+	before, err := u.snapshot(transaction, filmoteka.AuditUser, user.Id)
+	if err != nil {
+		return u.tx.ShutDown(transaction, err)
+	}
+
 	if err = u.dao.UpdateUser(transaction, login, userRole); err != nil {
 		return u.tx.ShutDown(transaction, err)
 	}
+
+	if err = u.record(transaction, callerId, filmoteka.AuditUpdate, filmoteka.AuditUser, user.Id, before); err != nil {
+		return u.tx.ShutDown(transaction, err)
+	}
 	return u.tx.Commit(transaction)
 }
 
+// DeleteUserById deletes the account of the user, who is the author of the change.
 func (u *UserService) DeleteUserById(id int) error {
 	transaction, err := u.tx.StartTransaction()
 	if err != nil {
 		return err
 	}
 
+	before, err := u.snapshot(transaction, filmoteka.AuditUser, id)
+	if err != nil {
+		return u.tx.ShutDown(transaction, err)
+	}
+
 	if err = u.dao.DeleteUserById(transaction, id); err != nil {
 		return u.tx.ShutDown(transaction, err)
 	}
+
+	if err = u.record(transaction, id, filmoteka.AuditDelete, filmoteka.AuditUser, id, before); err != nil {
+		return u.tx.ShutDown(transaction, err)
+	}
+	return u.tx.Commit(transaction)
+}
+
+func (u *UserService) rehashPassword(id int, password string) error {
+	hash, err := generateHashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	transaction, err := u.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err = u.dao.UpdatePassword(transaction, id, hash); err != nil {
+		return u.tx.ShutDown(transaction, err)
+	}
 	return u.tx.Commit(transaction)
 }
 
-func generateHashPassword(password string) string {
+func (u *UserService) issueTokens(transaction *sqlx.Tx, userId, version int) (filmoteka.TokenPair, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return filmoteka.TokenPair{}, err
+	}
+
+	now := time.Now()
+	accessToken, err := u.keys.sign(&tokenClaims{
+		UserId:  userId,
+		Version: version,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	if err != nil {
+		return filmoteka.TokenPair{}, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return filmoteka.TokenPair{}, err
+	}
+
+	err = u.tokens.CreateRefreshToken(transaction, userId, hashToken(refreshToken), now.Add(refreshTokenTTL))
+	if err != nil {
+		return filmoteka.TokenPair{}, err
+	}
+
+	return filmoteka.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (u *UserService) parseClaims(accessToken string) (*tokenClaims, error) {
+	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, u.keys.keyFunc)
+	if err != nil {
+		return nil, &filmoteka.Error{Code: filmoteka.CodeUnauthorized, Message: err.Error(), Err: err}
+	}
+
+	claims, ok := token.Claims.(*tokenClaims)
+	if !ok {
+		return nil, filmoteka.UnauthorizedError("token claims are not of type *tokenClaims")
+	}
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, filmoteka.UnauthorizedError("token has no id or expiration time")
+	}
+	return claims, nil
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken is used to store refresh tokens, so a leaked table cannot be replayed.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateHashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), hashCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// verifyPassword checks password against the stored hash and reports whether the
+// hash is outdated (legacy SHA-1 or weaker bcrypt cost) and has to be regenerated.
+func verifyPassword(hash, password string) (bool, error) {
+	if !strings.HasPrefix(hash, "$2") {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(legacyHashPassword(password))) != 1 {
+			return false, errInvalidCredentials
+		}
+		return true, nil
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return false, errInvalidCredentials
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false, err
+	}
+	return cost < hashCost, nil
+}
+
+func legacyHashPassword(password string) string {
 	hash := sha1.New()
 	hash.Write([]byte(password))
 
-	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
+	return fmt.Sprintf("%x", hash.Sum([]byte(legacySalt)))
 }
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryUsers keeps a single user, methods the sign in does not need are left to the
+// embedded interface.
+type memoryUsers struct {
+	models_dao.User
+	user       filmoteka.User
+	err        error
+	updated    string
+	updateErr  error
+	version    int
+	versionErr error
+}
+
+func (m *memoryUsers) GetUserByLogin(login string) (filmoteka.User, error) {
+	if m.err != nil {
+		return filmoteka.User{}, m.err
+	}
+	if login != m.user.Login {
+		return filmoteka.User{}, filmoteka.NotFoundError("user not found")
+	}
+	return m.user, nil
+}
+
+func (m *memoryUsers) GetTokenVersion(_ int) (int, error) {
+	return m.version, m.versionErr
+}
+
+func (m *memoryUsers) UpdatePassword(_ *sqlx.Tx, _ int, password string) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	m.updated = password
+	return nil
+}
+
+type memoryTokens struct {
+	models_dao.Token
+	stored  filmoteka.RefreshToken
+	err     error
+	issued  int
+	revoked int
+}
+
+func (m *memoryTokens) GetRefreshToken(tokenHash string) (filmoteka.RefreshToken, error) {
+	if m.err != nil {
+		return filmoteka.RefreshToken{}, m.err
+	}
+	if tokenHash != m.stored.TokenHash {
+		return filmoteka.RefreshToken{}, filmoteka.NotFoundError("requested record not found")
+	}
+	return m.stored, nil
+}
+
+func (m *memoryTokens) RevokeRefreshToken(_ *sqlx.Tx, _ int) error {
+	m.revoked++
+	return nil
+}
+
+func (m *memoryTokens) CreateRefreshToken(_ *sqlx.Tx, _ int, _ string, _ time.Time) error {
+	m.issued++
+	return nil
+}
+
+// memoryTransaction counts how the transactions of a service end.
+type memoryTransaction struct {
+	committed, shutDown int
+}
+
+func (m *memoryTransaction) StartTransaction() (*sqlx.Tx, error) { return nil, nil }
+
+func (m *memoryTransaction) ShutDown(_ *sqlx.Tx, err error) error {
+	m.shutDown++
+	return err
+}
+
+func (m *memoryTransaction) Commit(_ *sqlx.Tx) error {
+	m.committed++
+	return nil
+}
+
+func bcryptHash(t *testing.T, password string, cost int) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	require.NoError(t, err)
+	return string(hash)
+}
+
+func TestVerifyPassword(t *testing.T) {
+	longPassword := strings.Repeat("p", filmoteka.MaxPasswordLength+1)
+
+	testTable := []struct {
+		name           string
+		hash           string
+		password       string
+		expectedRehash bool
+		expectedErr    error
+	}{
+		{
+			name:           "Bcrypt",
+			hash:           bcryptHash(t, "qwerty", hashCost),
+			password:       "qwerty",
+			expectedRehash: false,
+		},
+		{
+			name:           "Weak Bcrypt Cost",
+			hash:           bcryptHash(t, "qwerty", bcrypt.MinCost),
+			password:       "qwerty",
+			expectedRehash: true,
+		},
+		{
+			name:           "Legacy SHA-1",
+			hash:           legacyHashPassword("qwerty"),
+			password:       "qwerty",
+			expectedRehash: true,
+		},
+		{
+			name:           "Legacy SHA-1 Long Password",
+			hash:           legacyHashPassword(longPassword),
+			password:       longPassword,
+			expectedRehash: true,
+		},
+		{
+			name:        "Wrong Bcrypt Password",
+			hash:        bcryptHash(t, "qwerty", hashCost),
+			password:    "asdfgh",
+			expectedErr: errInvalidCredentials,
+		},
+		{
+			name:        "Wrong Legacy Password",
+			hash:        legacyHashPassword("qwerty"),
+			password:    "asdfgh",
+			expectedErr: errInvalidCredentials,
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			rehash, err := verifyPassword(test.hash, test.password)
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedRehash, rehash)
+		})
+	}
+}
+
+func TestUserService_GenerateToken(t *testing.T) {
+	longPassword := strings.Repeat("p", filmoteka.MaxPasswordLength+1)
+	dbErr := errors.New("connection refused")
+
+	testTable := []struct {
+		name           string
+		users          *memoryUsers
+		login          string
+		password       string
+		expectedErr    error
+		expectedIssued int
+		expectedUpdate bool
+	}{
+		{
+			name:           "Bcrypt",
+			users:          &memoryUsers{user: filmoteka.User{Id: 1, Login: "user", Password: bcryptHash(t, "qwerty", hashCost)}},
+			login:          "user",
+			password:       "qwerty",
+			expectedIssued: 1,
+		},
+		{
+			name:           "Legacy Upgraded",
+			users:          &memoryUsers{user: filmoteka.User{Id: 1, Login: "user", Password: legacyHashPassword("qwerty")}},
+			login:          "user",
+			password:       "qwerty",
+			expectedIssued: 1,
+			expectedUpdate: true,
+		},
+		{
+			name:           "Upgrade Too Long Password",
+			users:          &memoryUsers{user: filmoteka.User{Id: 1, Login: "user", Password: legacyHashPassword(longPassword)}},
+			login:          "user",
+			password:       longPassword,
+			expectedIssued: 1,
+		},
+		{
+			name: "Upgrade Failed",
+			users: &memoryUsers{user: filmoteka.User{Id: 1, Login: "user", Password: legacyHashPassword("qwerty")},
+				updateErr: dbErr},
+			login:          "user",
+			password:       "qwerty",
+			expectedIssued: 1,
+		},
+		{
+			name:        "Wrong Password",
+			users:       &memoryUsers{user: filmoteka.User{Id: 1, Login: "user", Password: legacyHashPassword("qwerty")}},
+			login:       "user",
+			password:    "asdfgh",
+			expectedErr: errInvalidCredentials,
+		},
+		{
+			name:        "Unknown Login",
+			users:       &memoryUsers{user: filmoteka.User{Id: 1, Login: "user", Password: legacyHashPassword("qwerty")}},
+			login:       "another",
+			password:    "qwerty",
+			expectedErr: errInvalidCredentials,
+		},
+		{
+			name:        "Database Error",
+			users:       &memoryUsers{err: dbErr},
+			login:       "user",
+			password:    "qwerty",
+			expectedErr: dbErr,
+		},
+	}
+
+	keys, err := NewKeySet("test:HS256:secret", "")
+	require.NoError(t, err)
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			tokens := &memoryTokens{}
+			users := NewUserService(test.users, tokens, nil, keys, &memoryTransaction{})
+
+			pair, err := users.GenerateToken(test.login, test.password)
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedIssued, tokens.issued)
+			if test.expectedIssued > 0 {
+				assert.NotEmpty(t, pair.AccessToken)
+			}
+
+			if test.expectedUpdate {
+				rehash, err := verifyPassword(test.users.updated, test.password)
+				assert.NoError(t, err)
+				assert.False(t, rehash)
+			} else {
+				assert.Empty(t, test.users.updated)
+			}
+		})
+	}
+}
+
+func TestUserService_RefreshToken(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	stored := filmoteka.RefreshToken{Id: 4, UserId: 1, TokenHash: hashToken("refresh"),
+		ExpiresAt: time.Now().Add(time.Hour)}
+
+	testTable := []struct {
+		name           string
+		token          string
+		tokens         *memoryTokens
+		users          *memoryUsers
+		expectedErr    error
+		expectedIssued int
+	}{
+		{
+			name:           "Ok",
+			token:          "refresh",
+			tokens:         &memoryTokens{stored: stored},
+			users:          &memoryUsers{version: 2},
+			expectedIssued: 1,
+		},
+		{
+			name:        "Unknown Token",
+			token:       "another",
+			tokens:      &memoryTokens{stored: stored},
+			users:       &memoryUsers{},
+			expectedErr: errInvalidRefreshToken,
+		},
+		{
+			name:        "Token Lookup Failed",
+			token:       "refresh",
+			tokens:      &memoryTokens{stored: stored, err: dbErr},
+			users:       &memoryUsers{},
+			expectedErr: dbErr,
+		},
+		{
+			name:        "Deleted User",
+			token:       "refresh",
+			tokens:      &memoryTokens{stored: stored},
+			users:       &memoryUsers{versionErr: filmoteka.NotFoundError("requested record not found")},
+			expectedErr: errInvalidRefreshToken,
+		},
+		{
+			name:        "Version Lookup Failed",
+			token:       "refresh",
+			tokens:      &memoryTokens{stored: stored},
+			users:       &memoryUsers{versionErr: dbErr},
+			expectedErr: dbErr,
+		},
+	}
+
+	keys, err := NewKeySet("test:HS256:secret", "")
+	require.NoError(t, err)
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			tx := &memoryTransaction{}
+			users := NewUserService(test.users, test.tokens, nil, keys, tx)
+
+			pair, err := users.RefreshToken(test.token)
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedIssued, test.tokens.issued)
+			assert.Equal(t, test.expectedIssued, test.tokens.revoked)
+			if test.expectedIssued > 0 {
+				assert.NotEmpty(t, pair.AccessToken)
+				assert.Equal(t, 1, tx.committed)
+			} else {
+				assert.Equal(t, 0, tx.committed)
+			}
+		})
+	}
+}
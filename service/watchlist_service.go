@@ -0,0 +1,63 @@
+package service
+
+import (
+	"github.com/jorgini/filmoteka"
+	"github.com/jorgini/filmoteka/models_dao"
+	"time"
+)
+
+type WatchlistService struct {
+	dao models_dao.Watchlist
+	tx  models_dao.Transaction
+}
+
+func NewWatchlistService(dao models_dao.Watchlist, tx models_dao.Transaction) *WatchlistService {
+	return &WatchlistService{
+		dao: dao,
+		tx:  tx,
+	}
+}
+
+func (w *WatchlistService) AddToWatchlist(entry filmoteka.WatchlistEntry) error {
+	if entry.Status == filmoteka.Watched && entry.WatchedOn == nil {
+		today := filmoteka.Date(time.Now())
+		entry.WatchedOn = &today
+	}
+
+	transaction, err := w.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err = w.dao.AddToWatchlist(transaction, entry); err != nil {
+		return w.tx.ShutDown(transaction, err)
+	}
+	return w.tx.Commit(transaction)
+}
+
+func (w *WatchlistService) RemoveFromWatchlist(userId, filmId int) error {
+	transaction, err := w.tx.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err = w.dao.RemoveFromWatchlist(transaction, userId, filmId); err != nil {
+		return w.tx.ShutDown(transaction, err)
+	}
+	return w.tx.Commit(transaction)
+}
+
+// GetWatchlist returns a page of the films the user marked with the status and the number
+// of all of them.
+func (w *WatchlistService) GetWatchlist(userId int, status string, page, limit int) ([]filmoteka.WatchlistItem, int, error) {
+	items, err := w.dao.GetWatchlist(userId, status, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := w.dao.CountWatchlist(userId, status)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
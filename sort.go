@@ -0,0 +1,34 @@
+package filmoteka
+
+import "strings"
+
+// SortKey is one key of a list order, Column is the public name of the column.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// ParseSort reads a sort specification like "-rating,title", where a leading minus asks
+// for the descending order of the key. Only the allowed columns may be used, each once.
+func ParseSort(spec string, allowed map[string]struct{}) ([]SortKey, error) {
+	var keys []SortKey
+	used := make(map[string]struct{})
+
+	for _, part := range strings.Split(spec, ",") {
+		key := SortKey{Column: strings.TrimSpace(part)}
+		if strings.HasPrefix(key.Column, "-") {
+			key.Column, key.Desc = key.Column[1:], true
+		}
+
+		if _, ok := allowed[key.Column]; !ok {
+			return nil, ValidationError("invalid sort", map[string]string{"sort": "unknown column " + key.Column})
+		}
+		if _, ok := used[key.Column]; ok {
+			return nil, ValidationError("invalid sort", map[string]string{"sort": "repeated column " + key.Column})
+		}
+
+		used[key.Column] = struct{}{}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
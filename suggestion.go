@@ -0,0 +1,14 @@
+package filmoteka
+
+const (
+	SuggestionFilm  = "film"
+	SuggestionActor = "actor"
+)
+
+// Suggestion is an entry of the search box typeahead. Type tells whether Id is the id of
+// a film or of an actor, Text is the title or the full name shown to the user.
+type Suggestion struct {
+	Type string `json:"type" db:"type"`
+	Id   int    `json:"id" db:"id"`
+	Text string `json:"text" db:"text"`
+}
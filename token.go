@@ -0,0 +1,18 @@
+package filmoteka
+
+import "time"
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type RefreshToken struct {
+	Id        int       `json:"id" db:"id"`
+	UserId    int       `json:"user_id" db:"user_id"`
+	TokenHash string    `json:"-" db:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	Revoked   bool      `json:"revoked" db:"revoked"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -3,19 +3,35 @@ package filmoteka
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 )
 
+const (
+	RegularRole   = "regular"
+	EditorRole    = "editor"
+	ModeratorRole = "moderator"
+	AdminRole     = "admin"
+)
+
+var UserRoles = map[string]struct{}{RegularRole: {}, EditorRole: {}, ModeratorRole: {}, AdminRole: {}}
+
+// MaxPasswordLength is the number of bytes bcrypt hashes, longer passwords are refused by it.
+const MaxPasswordLength = 72
+
 type User struct {
 	Id       int    `json:"id" db:"id"`
 	Login    string `json:"login" db:"login"`
 	Password string `json:"password" db:"password"`
 	UserRole string `json:"user_role" db:"user_role"`
+	// TokenVersion is bumped whenever the tokens issued to the user must stop working.
+	TokenVersion int `json:"-" db:"token_version"`
 }
 
+// UnmarshalJSON reads the signup body. The role is not taken from the client, every new
+// user is regular and only moderators and admins may change the role later.
 func (u *User) UnmarshalJSON(data []byte) error {
 	result := struct {
 		Id       *int    `json:"id"`
-		UserRole *string `json:"user_role"`
 		Login    *string `json:"login"`
 		Password *string `json:"password"`
 	}{}
@@ -24,14 +40,16 @@ func (u *User) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	if result.UserRole == nil || result.Login == nil || result.Password == nil ||
-		(*result.UserRole != "regular" && *result.UserRole != "admin") {
+	if result.Login == nil || result.Password == nil {
 		return errors.New("invalid state for required field(s)")
+	} else if len(*result.Password) > MaxPasswordLength {
+		return ValidationError("password is too long",
+			map[string]string{"password": fmt.Sprintf("expected at most %d bytes", MaxPasswordLength)})
 	} else {
 		if result.Id != nil {
 			u.Id = *result.Id
 		}
-		u.UserRole = *result.UserRole
+		u.UserRole = RegularRole
 		u.Login = *result.Login
 		u.Password = *result.Password
 	}
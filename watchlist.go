@@ -0,0 +1,49 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	WantToWatch = "want"
+	Watched     = "watched"
+)
+
+type WatchlistEntry struct {
+	UserId    int    `json:"-" db:"user_id"`
+	FilmId    int    `json:"film_id" db:"film_id"`
+	Status    string `json:"status" db:"status"`
+	WatchedOn *Date  `json:"watched_on,omitempty" db:"watched_on"`
+}
+
+func (w *WatchlistEntry) UnmarshalJSON(data []byte) error {
+	result := struct {
+		FilmId    *int    `json:"film_id"`
+		Status    *string `json:"status"`
+		WatchedOn *Date   `json:"watched_on"`
+	}{}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	if result.FilmId == nil || result.Status == nil ||
+		(*result.Status != WantToWatch && *result.Status != Watched) ||
+		(*result.Status == WantToWatch && result.WatchedOn != nil) {
+		return errors.New("invalid state for required field(s)")
+	} else {
+		w.FilmId = *result.FilmId
+		w.Status = *result.Status
+		w.WatchedOn = result.WatchedOn
+	}
+	return nil
+}
+
+type WatchlistItem struct {
+	Film
+	Status    string    `json:"status" db:"status"`
+	WatchedOn *Date     `json:"watched_on,omitempty" db:"watched_on"`
+	AddedAt   time.Time `json:"added_at" db:"added_at"`
+}